@@ -0,0 +1,295 @@
+package barcode
+
+import "fmt"
+
+const (
+	// aztecMinECCPercent matches the default correction level Wallet uses.
+	aztecMinECCPercent = 23
+	aztecMaxLayers     = 32
+
+	aztecBinaryShift = 31
+	aztecMaxShortRun = 31
+	aztecMaxLongRun  = 2078
+)
+
+func aztecWordSize(layers int) int {
+	switch {
+	case layers <= 2:
+		return 6
+	case layers <= 8:
+		return 8
+	case layers <= 22:
+		return 10
+	}
+
+	return 12
+}
+
+func aztecField(wordSize int) *galoisField {
+	switch wordSize {
+	case 4:
+		return aztecField4
+	case 6:
+		return aztecField6
+	case 8:
+		return aztecField8
+	case 10:
+		return aztecField10
+	}
+
+	return aztecField12
+}
+
+func aztecTotalBits(layers int, compact bool) int {
+	if compact {
+		return (88 + 16*layers) * layers
+	}
+
+	return (112 + 16*layers) * layers
+}
+
+// aztecBits encodes data in binary shift runs from upper case mode, which can
+// hold any byte sequence.
+func aztecBits(data []byte) bitBuffer {
+	var bits bitBuffer
+	for len(data) > 0 {
+		n := min(len(data), aztecMaxLongRun)
+		switch {
+		case n <= aztecMaxShortRun:
+			bits.append(aztecBinaryShift, 5)
+			bits.append(n, 5)
+		case n <= 2*aztecMaxShortRun:
+			// Two short runs are one bit cheaper than a long one.
+			n = aztecMaxShortRun
+			bits.append(aztecBinaryShift, 5)
+			bits.append(n, 5)
+		default:
+			bits.append(aztecBinaryShift, 5)
+			bits.append(0, 5)
+			bits.append(n-aztecMaxShortRun, 11)
+		}
+
+		for _, b := range data[:n] {
+			bits.append(int(b), 8)
+		}
+		data = data[n:]
+	}
+
+	return bits
+}
+
+// aztecStuff splits bits into words, avoiding words of all zeros or all ones
+// by inserting a complementary bit.
+func aztecStuff(bits bitBuffer, wordSize int) bitBuffer {
+	var out bitBuffer
+	mask := 1<<wordSize - 2
+	for i := 0; i < len(bits); i += wordSize {
+		word := 0
+		for j := 0; j < wordSize; j++ {
+			if i+j >= len(bits) || bits[i+j] {
+				word |= 1 << (wordSize - 1 - j)
+			}
+		}
+
+		switch {
+		case word&mask == mask:
+			out.append(word&mask, wordSize)
+			i--
+		case word&mask == 0:
+			out.append(word|1, wordSize)
+			i--
+		default:
+			out.append(word, wordSize)
+		}
+	}
+
+	return out
+}
+
+func aztecCheckWords(bits bitBuffer, totalBits, wordSize int) bitBuffer {
+	words := make([]int, len(bits)/wordSize)
+	for i := range words {
+		for j := 0; j < wordSize; j++ {
+			words[i] <<= 1
+			if bits[i*wordSize+j] {
+				words[i] |= 1
+			}
+		}
+	}
+
+	ecc := aztecField(wordSize).ecc(words, totalBits/wordSize-len(words))
+
+	var out bitBuffer
+	out.append(0, totalBits%wordSize)
+	for _, w := range append(words, ecc...) {
+		out.append(w, wordSize)
+	}
+
+	return out
+}
+
+func encodeAztec(data []byte) (*Matrix, error) {
+	bits := aztecBits(data)
+	eccBits := len(bits)*aztecMinECCPercent/100 + 11
+	totalSize := len(bits) + eccBits
+
+	var (
+		compact  bool
+		layers   int
+		wordSize int
+		stuffed  bitBuffer
+		total    int
+	)
+
+	for i := 0; ; i++ {
+		if i > aztecMaxLayers {
+			return nil, fmt.Errorf("Message of %d bytes does not fit in an Aztec code", len(data))
+		}
+
+		compact = i <= 3
+		layers = i
+		if compact {
+			layers = i + 1
+		}
+
+		total = aztecTotalBits(layers, compact)
+		if totalSize > total {
+			continue
+		}
+
+		if ws := aztecWordSize(layers); ws != wordSize {
+			wordSize = ws
+			stuffed = aztecStuff(bits, wordSize)
+		}
+
+		usable := total - total%wordSize
+		if compact && len(stuffed) > wordSize*64 {
+			continue
+		}
+		if len(stuffed)+eccBits <= usable {
+			break
+		}
+	}
+
+	message := aztecCheckWords(stuffed, total, wordSize)
+	messageWords := len(stuffed) / wordSize
+
+	var mode bitBuffer
+	if compact {
+		mode.append(layers-1, 2)
+		mode.append(messageWords-1, 6)
+		mode = aztecCheckWords(mode, 28, 4)
+	} else {
+		mode.append(layers-1, 5)
+		mode.append(messageWords-1, 11)
+		mode = aztecCheckWords(mode, 40, 4)
+	}
+
+	baseSize := 14 + layers*4
+	if compact {
+		baseSize = 11 + layers*4
+	}
+
+	align := make([]int, baseSize)
+	size := baseSize
+	if compact {
+		for i := range align {
+			align[i] = i
+		}
+	} else {
+		size = baseSize + 1 + 2*((baseSize/2-1)/15)
+		origCenter, center := baseSize/2, size/2
+		for i := 0; i < origCenter; i++ {
+			offset := i + i/15
+			align[origCenter-i-1] = center - offset - 1
+			align[origCenter+i] = center + offset + 1
+		}
+	}
+
+	m := NewMatrix(size, size)
+
+	rowOffset := 0
+	for i := 0; i < layers; i++ {
+		rowSize := (layers-i)*4 + 12
+		if compact {
+			rowSize = (layers-i)*4 + 9
+		}
+
+		for j := 0; j < rowSize; j++ {
+			col := j * 2
+			for k := 0; k < 2; k++ {
+				if message[rowOffset+col+k] {
+					m.Set(align[i*2+k], align[i*2+j], true)
+				}
+				if message[rowOffset+rowSize*2+col+k] {
+					m.Set(align[i*2+j], align[baseSize-1-i*2-k], true)
+				}
+				if message[rowOffset+rowSize*4+col+k] {
+					m.Set(align[baseSize-1-i*2-k], align[baseSize-1-i*2-j], true)
+				}
+				if message[rowOffset+rowSize*6+col+k] {
+					m.Set(align[baseSize-1-i*2-j], align[i*2+k], true)
+				}
+			}
+		}
+		rowOffset += rowSize * 8
+	}
+
+	center := size / 2
+	aztecModeMessage(m, compact, center, mode)
+
+	if compact {
+		aztecBullsEye(m, center, 5)
+	} else {
+		aztecBullsEye(m, center, 7)
+		for i, j := 0, 0; i < baseSize/2-1; i, j = i+15, j+16 {
+			for k := center & 1; k < size; k += 2 {
+				m.Set(center-j, k, true)
+				m.Set(center+j, k, true)
+				m.Set(k, center-j, true)
+				m.Set(k, center+j, true)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func aztecModeMessage(m *Matrix, compact bool, center int, mode bitBuffer) {
+	if compact {
+		for i := 0; i < 7; i++ {
+			offset := center - 3 + i
+			m.Set(offset, center-5, mode[i])
+			m.Set(center+5, offset, mode[i+7])
+			m.Set(offset, center+5, mode[20-i])
+			m.Set(center-5, offset, mode[27-i])
+		}
+		return
+	}
+
+	for i := 0; i < 10; i++ {
+		offset := center - 5 + i + i/5
+		m.Set(offset, center-7, mode[i])
+		m.Set(center+7, offset, mode[i+10])
+		m.Set(offset, center+7, mode[29-i])
+		m.Set(center-7, offset, mode[39-i])
+	}
+}
+
+func aztecBullsEye(m *Matrix, center, size int) {
+	for i := 0; i < size; i += 2 {
+		for j := center - i; j <= center+i; j++ {
+			m.Set(j, center-i, true)
+			m.Set(j, center+i, true)
+			m.Set(center-i, j, true)
+			m.Set(center+i, j, true)
+		}
+	}
+
+	m.Set(center-size, center-size, true)
+	m.Set(center-size+1, center-size, true)
+	m.Set(center-size, center-size+1, true)
+	m.Set(center+size, center-size, true)
+	m.Set(center+size, center-size+1, true)
+	m.Set(center+size, center+size-1, true)
+}
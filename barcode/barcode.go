@@ -0,0 +1,241 @@
+// Package barcode renders the barcodes of a pass as PNG or SVG images, so the
+// code Wallet shows can also be used in emails and previews.
+package barcode
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/clevtech/apple-wallet-pass/internal/font"
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+const (
+	DefaultScale = 4
+)
+
+var errEmptyMessage = errors.New("Barcode message can not be empty")
+
+// Matrix is a grid of modules, true for dark.
+type Matrix struct {
+	width   int
+	height  int
+	modules []bool
+}
+
+func NewMatrix(width, height int) *Matrix {
+	return &Matrix{width: width, height: height, modules: make([]bool, width*height)}
+}
+
+func (m *Matrix) Width() int {
+	return m.width
+}
+
+func (m *Matrix) Height() int {
+	return m.height
+}
+
+func (m *Matrix) Get(x, y int) bool {
+	return m.modules[y*m.width+x]
+}
+
+func (m *Matrix) Set(x, y int, dark bool) {
+	m.modules[y*m.width+x] = dark
+}
+
+func (m *Matrix) sameRow(a, b int) bool {
+	for x := 0; x < m.width; x++ {
+		if m.Get(x, a) != m.Get(x, b) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Encode encodes the barcode message, converted to its MessageEncoding, in
+// the symbology of its Format.
+func Encode(b passkit.Barcodes) (*Matrix, error) {
+	if b.Message == "" {
+		return nil, errEmptyMessage
+	}
+
+	encoding := b.MessageEncoding
+	if encoding == "" {
+		encoding = passkit.DefaultMessageEncoding
+	}
+
+	data, err := passkit.EncodeBarcodeMessage(b.Message, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.Format {
+	case passkit.BarcodeFormatQR:
+		return encodeQR(data)
+	case passkit.BarcodeFormatPDF417:
+		return encodePDF417(data)
+	case passkit.BarcodeFormatAztec:
+		return encodeAztec(data)
+	case passkit.BarcodeFormatCode128:
+		return encodeCode128(data)
+	}
+
+	return nil, fmt.Errorf("Unknown barcode format %q", b.Format)
+}
+
+func quietZone(format passkit.BarcodeFormat) int {
+	switch format {
+	case passkit.BarcodeFormatQR:
+		return 4
+	case passkit.BarcodeFormatCode128:
+		return 10
+	}
+
+	return 2
+}
+
+// layout holds the pixel geometry shared by the PNG and SVG renderers.
+type layout struct {
+	matrix    *Matrix
+	scale     int
+	textScale int
+	offsetX   int
+	offsetY   int
+	width     int
+	height    int
+	text      string
+}
+
+func newLayout(b passkit.Barcodes, scale int) (*layout, error) {
+	m, err := Encode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if scale <= 0 {
+		scale = DefaultScale
+	}
+
+	quiet := quietZone(b.Format) * scale
+	l := &layout{
+		matrix:    m,
+		scale:     scale,
+		textScale: max(1, scale/2),
+		offsetX:   quiet,
+		offsetY:   quiet,
+		width:     m.Width()*scale + 2*quiet,
+		height:    m.Height()*scale + 2*quiet,
+		text:      b.AltText,
+	}
+
+	if l.text != "" {
+		textWidth := font.TextWidth(l.text, l.textScale) + 2*quiet
+		if textWidth > l.width {
+			l.offsetX += (textWidth - l.width) / 2
+			l.width = textWidth
+		}
+		l.height += font.Height*l.textScale + quiet
+	}
+
+	return l, nil
+}
+
+// Image renders the barcode with scale pixels per module and the alt text,
+// if any, underneath.
+func Image(b passkit.Barcodes, scale int) (image.Image, error) {
+	l, err := newLayout(b, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewGray(image.Rect(0, 0, l.width, l.height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	fill := func(x, y, w, h int) {
+		for yy := y; yy < y+h; yy++ {
+			for xx := x; xx < x+w; xx++ {
+				img.SetGray(xx, yy, color.Gray{})
+			}
+		}
+	}
+
+	m := l.matrix
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			if m.Get(x, y) {
+				fill(l.offsetX+x*l.scale, l.offsetY+y*l.scale, l.scale, l.scale)
+			}
+		}
+	}
+
+	if l.text != "" {
+		x0 := (l.width - font.TextWidth(l.text, l.textScale)) / 2
+		font.Draw(img, x0, l.offsetY*2+m.Height()*l.scale, l.text, l.textScale, color.Gray{})
+	}
+
+	return img, nil
+}
+
+func WritePNG(w io.Writer, b passkit.Barcodes, scale int) error {
+	img, err := Image(b, scale)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
+
+// WriteSVG renders the barcode as SVG, drawing each horizontal run of dark
+// modules as one rectangle that spans all identical rows below it.
+func WriteSVG(w io.Writer, b passkit.Barcodes, scale int) error {
+	l, err := newLayout(b, scale)
+	if err != nil {
+		return err
+	}
+
+	var path strings.Builder
+	m := l.matrix
+	for y := 0; y < m.Height(); {
+		rows := 1
+		for y+rows < m.Height() && m.sameRow(y, y+rows) {
+			rows++
+		}
+
+		for x := 0; x < m.Width(); {
+			if !m.Get(x, y) {
+				x++
+				continue
+			}
+
+			start := x
+			for x < m.Width() && m.Get(x, y) {
+				x++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv%dh-%dz", l.offsetX+start*l.scale, l.offsetY+y*l.scale, (x-start)*l.scale, rows*l.scale, (x-start)*l.scale)
+		}
+		y += rows
+	}
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, l.width, l.height, l.width, l.height)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="#fff"/>`)
+	fmt.Fprintf(w, `<path fill="#000" d="%s"/>`, path.String())
+
+	if l.text != "" {
+		size := font.Height * l.textScale
+		y := l.offsetY*2 + m.Height()*l.scale + size*3/4
+		fmt.Fprintf(w, `<text x="%d" y="%d" font-family="monospace" font-size="%d" text-anchor="middle">%s</text>`, l.width/2, y, size, html.EscapeString(l.text))
+	}
+
+	_, err = io.WriteString(w, "</svg>\n")
+
+	return err
+}
@@ -0,0 +1,364 @@
+package barcode
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+func TestEncodeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		barcode passkit.Barcodes
+	}{
+		{
+			name:    "empty message",
+			barcode: passkit.Barcodes{Format: passkit.BarcodeFormatQR},
+		},
+		{
+			name:    "unknown format",
+			barcode: passkit.Barcodes{Format: "PKBarcodeFormatDataMatrix", Message: "ABC"},
+		},
+		{
+			name:    "unsupported encoding",
+			barcode: passkit.Barcodes{Format: passkit.BarcodeFormatQR, Message: "ABC", MessageEncoding: "koi8-r"},
+		},
+		{
+			name:    "not representable",
+			barcode: passkit.Barcodes{Format: passkit.BarcodeFormatQR, Message: "€", MessageEncoding: "iso-8859-1"},
+		},
+		{
+			name:    "code128 non-ASCII",
+			barcode: passkit.Barcodes{Format: passkit.BarcodeFormatCode128, Message: "café"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Encode(tt.barcode); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := Encode(passkit.Barcodes{Format: passkit.BarcodeFormatQR}); !errors.Is(err, errEmptyMessage) {
+		t.Errorf("empty message error = %v, want %v", err, errEmptyMessage)
+	}
+}
+
+func TestEncodeMessageEncoding(t *testing.T) {
+	tests := []struct {
+		encoding string
+		want     string
+	}{
+		{encoding: "", want: "caf\xe9"},
+		{encoding: "iso-8859-1", want: "caf\xe9"},
+		{encoding: "utf-8", want: "café"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			m, err := Encode(passkit.Barcodes{Format: passkit.BarcodeFormatQR, Message: "café", MessageEncoding: tt.encoding})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			version, data := decodeQR(t, m)
+			if got := qrMessage(t, version, data); got != tt.want {
+				t.Errorf("message = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// decodePDF417 reads the codewords of every row, checking the start and stop
+// patterns and the row indicators' cluster.
+func decodePDF417(t *testing.T, m *Matrix) [][]int {
+	t.Helper()
+
+	if m.Height()%pdf417RowHeight != 0 || (m.Width()-1)%17 != 0 {
+		t.Fatalf("symbol is %dx%d", m.Width(), m.Height())
+	}
+	cols := (m.Width()-1)/17 - 4
+
+	var rows [][]int
+	for r := 0; r*pdf417RowHeight < m.Height(); r++ {
+		y := r * pdf417RowHeight
+		for dy := 1; dy < pdf417RowHeight; dy++ {
+			if !m.sameRow(y, y+dy) {
+				t.Fatalf("row %d is not %d modules high", r, pdf417RowHeight)
+			}
+		}
+
+		read := func(x, n int) uint32 {
+			var v uint32
+			for i := 0; i < n; i++ {
+				v <<= 1
+				if m.Get(x+i, y) {
+					v |= 1
+				}
+			}
+			return v
+		}
+
+		if got := read(0, 17); got != pdf417Start {
+			t.Fatalf("row %d starts with %017b", r, got)
+		}
+		if got := read(m.Width()-18, 18); got != pdf417Stop {
+			t.Fatalf("row %d ends with %018b", r, got)
+		}
+
+		index := make(map[uint32]int, len(pdf417Patterns[r%3]))
+		for cw, p := range pdf417Patterns[r%3] {
+			index[p] = cw
+		}
+
+		var row []int
+		for c := 0; c < cols+2; c++ {
+			p := read(17*(c+1), 17)
+			cw, ok := index[p]
+			if !ok {
+				t.Fatalf("row %d column %d: %017b is not in cluster %d", r, c, p, r%3*3)
+			}
+			row = append(row, cw)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func TestEncodePDF417(t *testing.T) {
+	for _, message := range []string{"ABC", "123456", "pass.com.example/ABC123?token=abcdef"} {
+		t.Run(message, func(t *testing.T) {
+			m, err := encodePDF417([]byte(message))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var words []int
+			for _, row := range decodePDF417(t, m) {
+				words = append(words, row[1:len(row)-1]...)
+			}
+
+			dataLen := words[0]
+			if dataLen <= 0 || dataLen > len(words) {
+				t.Fatalf("symbol length descriptor = %d", dataLen)
+			}
+
+			level, err := pdf417Level(len(pdf417Codewords([]byte(message))) + 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(words) - dataLen; got != 2<<level {
+				t.Errorf("%d error correction codewords, want %d", got, 2<<level)
+			}
+
+			// Byte compaction packs groups of 6 bytes into 5 base 900
+			// codewords and keeps the rest as single bytes.
+			data := words[1:dataLen]
+			for len(data) > 0 && data[len(data)-1] == pdf417Pad {
+				data = data[:len(data)-1]
+			}
+			if len(data) == 0 || (data[0] != pdf417LatchByte && data[0] != pdf417LatchByteMod) {
+				t.Fatalf("data does not start with a byte compaction latch: %v", data)
+			}
+			data = data[1:]
+
+			var got []byte
+			for len(data) >= 5 && len(got)+6 <= len(message) {
+				var v uint64
+				for _, cw := range data[:5] {
+					v = v*900 + uint64(cw)
+				}
+				for i := 5; i >= 0; i-- {
+					got = append(got, byte(v>>(8*i)))
+				}
+				data = data[5:]
+			}
+			for _, cw := range data {
+				got = append(got, byte(cw))
+			}
+
+			if string(got) != message {
+				t.Errorf("message = %q, want %q", got, message)
+			}
+		})
+	}
+}
+
+func TestEncodeAztec(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		compact bool
+	}{
+		{name: "compact", message: "ABC123", compact: true},
+		{name: "full", message: strings.Repeat("pass.com.example ", 10), compact: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := encodeAztec([]byte(tt.message))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if m.Width() != m.Height() {
+				t.Fatalf("symbol is %dx%d", m.Width(), m.Height())
+			}
+
+			center, rings := m.Width()/2, 4
+			if !tt.compact {
+				rings = 6
+			}
+
+			// The bullseye alternates dark and light square rings out from a
+			// dark center.
+			for y := center - rings; y <= center+rings; y++ {
+				for x := center - rings; x <= center+rings; x++ {
+					d := max(abs(x-center), abs(y-center))
+					if m.Get(x, y) != (d%2 == 0) {
+						t.Fatalf("bullseye module (%d, %d) at ring %d is wrong", x, y, d)
+					}
+				}
+			}
+
+			// The orientation marks sit in three corners of the mode message
+			// ring.
+			r := rings + 1
+			for _, p := range [][2]int{{-r, -r}, {-r + 1, -r}, {-r, -r + 1}, {r, -r}, {r, -r + 1}, {r, r - 1}} {
+				if !m.Get(center+p[0], center+p[1]) {
+					t.Errorf("orientation mark at %v is light", p)
+				}
+			}
+
+			if tt.compact {
+				var mode bitBuffer
+				for i := 0; i < 7; i++ {
+					mode = append(mode, m.Get(center-3+i, center-5))
+				}
+				for i := 0; i < 7; i++ {
+					mode = append(mode, m.Get(center+5, center-3+i))
+				}
+				if got := aztecCheckWords(mode[:8], 28, 4)[:14]; !equalBits(got, mode) {
+					t.Errorf("mode message check words do not match")
+				}
+
+				layers := 0
+				for _, bit := range mode[:2] {
+					layers <<= 1
+					if bit {
+						layers |= 1
+					}
+				}
+				if m.Width() != 11+4*(layers+1) {
+					t.Errorf("size %d does not match %d layers", m.Width(), layers+1)
+				}
+			}
+		})
+	}
+}
+
+func equalBits(a, b bitBuffer) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestWritePNG(t *testing.T) {
+	b := passkit.Barcodes{Format: passkit.BarcodeFormatQR, Message: "HELLO WORLD"}
+
+	tests := []struct {
+		name          string
+		altText       string
+		scale         int
+		width, height int
+	}{
+		{name: "scale 2", scale: 2, width: 21*2 + 2*4*2, height: 21*2 + 2*4*2},
+		{name: "default scale", scale: 0, width: 21*4 + 2*4*4, height: 21*4 + 2*4*4},
+		{name: "alt text", altText: "HELLO", scale: 2, width: 21*2 + 2*4*2, height: 21*2 + 2*4*2 + 13 + 4*2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b.AltText = tt.altText
+
+			var buf bytes.Buffer
+			if err := WritePNG(&buf, b, tt.scale); err != nil {
+				t.Fatal(err)
+			}
+
+			img, err := png.Decode(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := img.Bounds().Size(); got.X != tt.width || got.Y != tt.height {
+				t.Errorf("size = %v, want %dx%d", got, tt.width, tt.height)
+			}
+
+			scale := tt.scale
+			if scale == 0 {
+				scale = DefaultScale
+			}
+			quiet := quietZone(b.Format) * scale
+			if r, _, _, _ := img.At(0, 0).RGBA(); r != 0xffff {
+				t.Error("quiet zone is not white")
+			}
+			if r, _, _, _ := img.At(quiet, quiet).RGBA(); r != 0 {
+				t.Error("top left finder module is not black")
+			}
+		})
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	b := passkit.Barcodes{Format: passkit.BarcodeFormatCode128, Message: "ABC123", AltText: `<A & "B">`}
+
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, b, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	svg := buf.String()
+	if !strings.Contains(svg, "&lt;A &amp; &#34;B&#34;&gt;") {
+		t.Errorf("alt text is not escaped: %s", svg)
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	var text string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v", err)
+		}
+		if cd, ok := tok.(xml.CharData); ok {
+			text += string(cd)
+		}
+	}
+
+	if text = strings.TrimSpace(text); text != b.AltText {
+		t.Errorf("text = %q, want %q", text, b.AltText)
+	}
+
+	if err := WriteSVG(io.Discard, passkit.Barcodes{Format: passkit.BarcodeFormatQR}, 2); err == nil {
+		t.Error("expected an error for an empty message")
+	}
+}
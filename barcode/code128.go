@@ -0,0 +1,144 @@
+package barcode
+
+import "fmt"
+
+// code128Patterns holds the bar and space widths of each Code 128 symbol,
+// starting with a bar. 103 to 105 are the start symbols, 106 is stop.
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128CodeA  = 101
+	code128StartA = 103
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+
+	code128Height = 40
+)
+
+// code128Symbols converts data into symbol values, switching to code set C
+// for runs of digits and to code set A for control characters.
+func code128Symbols(data []byte) ([]int, error) {
+	for _, c := range data {
+		if c > 127 {
+			return nil, fmt.Errorf("Code128 can not encode byte 0x%02x", c)
+		}
+	}
+
+	digits := func(i int) int {
+		n := 0
+		for i+n < len(data) && data[i+n] >= '0' && data[i+n] <= '9' {
+			n++
+		}
+		return n
+	}
+
+	var symbols []int
+	set := 0
+	for i := 0; i < len(data); {
+		if run := digits(i); run >= 2 && (set == 'C' || run >= 4 && run%2 == 0) {
+			if set != 'C' {
+				symbols = append(symbols, code128Switch(set, 'C'))
+				set = 'C'
+			}
+			for ; run >= 2; run -= 2 {
+				symbols = append(symbols, int(data[i]-'0')*10+int(data[i+1]-'0'))
+				i += 2
+			}
+			continue
+		}
+
+		c := data[i]
+		want := set
+		switch {
+		case c < 32:
+			want = 'A'
+		case c >= 96, set == 0, set == 'C':
+			want = 'B'
+		}
+
+		if want != set {
+			symbols = append(symbols, code128Switch(set, want))
+			set = want
+		}
+
+		if c < 32 {
+			symbols = append(symbols, int(c)+64)
+		} else {
+			symbols = append(symbols, int(c)-32)
+		}
+		i++
+	}
+
+	if len(symbols) == 0 {
+		return nil, errEmptyMessage
+	}
+
+	return symbols, nil
+}
+
+func code128Switch(from, to int) int {
+	if from == 0 {
+		switch to {
+		case 'A':
+			return code128StartA
+		case 'C':
+			return code128StartC
+		}
+		return code128StartB
+	}
+
+	switch to {
+	case 'A':
+		return code128CodeA
+	case 'C':
+		return code128CodeC
+	}
+
+	return code128CodeB
+}
+
+func encodeCode128(data []byte) (*Matrix, error) {
+	symbols, err := code128Symbols(data)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := symbols[0]
+	for i, s := range symbols[1:] {
+		checksum += (i + 1) * s
+	}
+	symbols = append(symbols, checksum%103, code128Stop)
+
+	var bars []bool
+	for _, s := range symbols {
+		for i, w := range code128Patterns[s] {
+			for j := 0; j < int(w-'0'); j++ {
+				bars = append(bars, i%2 == 0)
+			}
+		}
+	}
+
+	m := NewMatrix(len(bars), code128Height)
+	for y := 0; y < code128Height; y++ {
+		for x, dark := range bars {
+			m.Set(x, y, dark)
+		}
+	}
+
+	return m, nil
+}
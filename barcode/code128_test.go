@@ -0,0 +1,115 @@
+package barcode
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// decodeCode128 reads the symbol values back from the bar widths of the
+// first row, checking the checksum and the stop symbol.
+func decodeCode128(t *testing.T, m *Matrix) []int {
+	t.Helper()
+
+	var widths strings.Builder
+	for x := 0; x < m.Width(); {
+		run := 1
+		for x+run < m.Width() && m.Get(x+run, 0) == m.Get(x, 0) {
+			run++
+		}
+		if m.Get(x, 0) != (widths.Len()%2 == 0) {
+			t.Fatalf("run at %d has the wrong color", x)
+		}
+		widths.WriteByte(byte('0' + run))
+		x += run
+	}
+
+	index := make(map[string]int, len(code128Patterns))
+	for i, p := range code128Patterns {
+		index[p] = i
+	}
+
+	// The stop symbol ends with a bar, so its 7 elements merge with nothing.
+	s := widths.String()
+	if len(s) < 7 || (len(s)-7)%6 != 0 {
+		t.Fatalf("%d bar and space widths do not split into symbols", len(s))
+	}
+	if stop, ok := index[s[len(s)-7:]]; !ok || stop != code128Stop {
+		t.Fatalf("symbol does not end with the stop pattern")
+	}
+
+	var symbols []int
+	for i := 0; i < len(s)-7; i += 6 {
+		v, ok := index[s[i:i+6]]
+		if !ok {
+			t.Fatalf("unknown pattern %s at element %d", s[i:i+6], i)
+		}
+		symbols = append(symbols, v)
+	}
+
+	checksum := symbols[0]
+	for i, v := range symbols[1 : len(symbols)-1] {
+		checksum += (i + 1) * v
+	}
+	if got := symbols[len(symbols)-1]; got != checksum%103 {
+		t.Fatalf("checksum = %d, want %d", got, checksum%103)
+	}
+
+	return symbols[:len(symbols)-1]
+}
+
+func TestEncodeCode128(t *testing.T) {
+	tests := []struct {
+		message string
+		symbols []int
+	}{
+		{
+			message: "PJJ123C",
+			symbols: []int{code128StartB, 48, 42, 42, 17, 18, 19, 35},
+		},
+		{
+			message: "12345678",
+			symbols: []int{code128StartC, 12, 34, 56, 78},
+		},
+		{
+			message: "AB1234",
+			symbols: []int{code128StartB, 33, 34, code128CodeC, 12, 34},
+		},
+		{
+			message: "1234AB",
+			symbols: []int{code128StartC, 12, 34, code128CodeB, 33, 34},
+		},
+		{
+			message: "A\tb",
+			symbols: []int{code128StartB, 33, code128CodeA, 73, code128CodeB, 66},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			m, err := encodeCode128([]byte(tt.message))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if m.Height() != code128Height {
+				t.Errorf("height = %d, want %d", m.Height(), code128Height)
+			}
+			for y := 1; y < m.Height(); y++ {
+				if !m.sameRow(0, y) {
+					t.Fatalf("row %d differs from row 0", y)
+				}
+			}
+
+			if got := decodeCode128(t, m); !slices.Equal(got, tt.symbols) {
+				t.Errorf("symbols = %v, want %v", got, tt.symbols)
+			}
+		})
+	}
+}
+
+func TestEncodeCode128NonASCII(t *testing.T) {
+	if _, err := encodeCode128([]byte("caf\xc3\xa9")); err == nil {
+		t.Error("expected an error for bytes above 127")
+	}
+}
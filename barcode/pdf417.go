@@ -0,0 +1,155 @@
+package barcode
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	pdf417Start = 0x1fea8
+	pdf417Stop  = 0x3fa29
+
+	pdf417LatchByte    = 901
+	pdf417LatchByteMod = 924
+	pdf417Pad          = 900
+
+	pdf417MaxCodewords = 928
+	pdf417MinLevel     = 2
+	pdf417RowHeight    = 3
+	pdf417AspectRatio  = 3.0
+)
+
+// pdf417Codewords encodes data with byte compaction, which can hold any
+// payload, and prefixes the latch codeword.
+func pdf417Codewords(data []byte) []int {
+	cw := []int{pdf417LatchByte}
+	if len(data)%6 == 0 {
+		cw[0] = pdf417LatchByteMod
+	}
+
+	i := 0
+	for ; i+6 <= len(data); i += 6 {
+		var v uint64
+		for _, b := range data[i : i+6] {
+			v = v<<8 | uint64(b)
+		}
+
+		var group [5]int
+		for j := 4; j >= 0; j-- {
+			group[j] = int(v % 900)
+			v /= 900
+		}
+		cw = append(cw, group[:]...)
+	}
+
+	for ; i < len(data); i++ {
+		cw = append(cw, int(data[i]))
+	}
+
+	return cw
+}
+
+// pdf417Level returns the error correction level recommended for n data
+// codewords, lowered as far as level 2 when the symbol would not fit.
+func pdf417Level(n int) (int, error) {
+	level := 5
+	switch {
+	case n <= 40:
+		level = 2
+	case n <= 160:
+		level = 3
+	case n <= 320:
+		level = 4
+	}
+
+	for ; level >= pdf417MinLevel; level-- {
+		if n+(2<<level) <= pdf417MaxCodewords {
+			return level, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Message needs %d codewords, PDF417 holds at most %d", n, pdf417MaxCodewords)
+}
+
+func pdf417Dimensions(total int) (cols, rows int) {
+	best := math.MaxFloat64
+	for c := 1; c <= 30; c++ {
+		r := max(3, (total+c-1)/c)
+		if r > 90 || c*r > pdf417MaxCodewords {
+			continue
+		}
+
+		ratio := float64(17*c+69) / float64(r*pdf417RowHeight)
+		if d := math.Abs(ratio - pdf417AspectRatio); d < best {
+			best, cols, rows = d, c, r
+		}
+	}
+
+	return cols, rows
+}
+
+func encodePDF417(data []byte) (*Matrix, error) {
+	codewords := pdf417Codewords(data)
+
+	// One extra codeword for the symbol length descriptor.
+	level, err := pdf417Level(len(codewords) + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	eccLen := 2 << level
+	cols, rows := pdf417Dimensions(len(codewords) + 1 + eccLen)
+	if cols == 0 {
+		return nil, fmt.Errorf("Message of %d bytes does not fit in a PDF417 code", len(data))
+	}
+
+	dataLen := cols*rows - eccLen
+	words := make([]int, 0, cols*rows)
+	words = append(words, dataLen)
+	words = append(words, codewords...)
+	for len(words) < dataLen {
+		words = append(words, pdf417Pad)
+	}
+	words = append(words, pdf417ECC(words, eccLen)...)
+
+	width := 17*(cols+4) + 1
+	m := NewMatrix(width, rows*pdf417RowHeight)
+
+	for r := 0; r < rows; r++ {
+		cluster := r % 3
+		base := r / 3 * 30
+		left, right := 0, 0
+		switch cluster {
+		case 0:
+			left = base + (rows-1)/3
+			right = base + cols - 1
+		case 1:
+			left = base + level*3 + (rows-1)%3
+			right = base + (rows-1)/3
+		case 2:
+			left = base + cols - 1
+			right = base + level*3 + (rows-1)%3
+		}
+
+		x := 0
+		put := func(pattern uint32, n int) {
+			for i := n - 1; i >= 0; i-- {
+				dark := pattern>>i&1 == 1
+				for y := 0; y < pdf417RowHeight; y++ {
+					m.Set(x, r*pdf417RowHeight+y, dark)
+				}
+				x++
+			}
+		}
+
+		put(pdf417Start, 17)
+		put(pdf417Patterns[cluster][left], 17)
+		for _, w := range words[r*cols : (r+1)*cols] {
+			put(pdf417Patterns[cluster][w], 17)
+		}
+		put(pdf417Patterns[cluster][right], 17)
+		put(pdf417Stop, 18)
+	}
+
+	return m, nil
+}
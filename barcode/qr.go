@@ -0,0 +1,512 @@
+package barcode
+
+import "fmt"
+
+// QR codes are encoded at error correction level M, the level Wallet uses.
+var (
+	qrECCPerBlockM = [41]int{-1,
+		10, 16, 26, 18, 24, 16, 18, 22, 22, 26,
+		30, 22, 22, 24, 24, 28, 28, 26, 26, 26,
+		26, 28, 28, 28, 28, 28, 28, 28, 28, 28,
+		28, 28, 28, 28, 28, 28, 28, 28, 28, 28,
+	}
+	qrBlocksM = [41]int{-1,
+		1, 1, 1, 2, 2, 4, 4, 4, 5, 5,
+		5, 8, 9, 9, 10, 10, 11, 13, 14, 16,
+		17, 17, 18, 20, 21, 23, 25, 26, 28, 29,
+		31, 33, 35, 37, 38, 40, 43, 45, 47, 49,
+	}
+)
+
+const qrAlphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+type qrMode struct {
+	indicator int
+	countBits [3]int
+}
+
+var (
+	qrModeNumeric      = qrMode{0x1, [3]int{10, 12, 14}}
+	qrModeAlphanumeric = qrMode{0x2, [3]int{9, 11, 13}}
+	qrModeByte         = qrMode{0x4, [3]int{8, 16, 16}}
+)
+
+func (m qrMode) charCountBits(version int) int {
+	switch {
+	case version <= 9:
+		return m.countBits[0]
+	case version <= 26:
+		return m.countBits[1]
+	}
+
+	return m.countBits[2]
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+// qrSegment picks the most compact single mode that can hold data.
+func qrSegment(data []byte) (qrMode, bitBuffer) {
+	numeric, alphanumeric := true, true
+	for _, c := range data {
+		if c < '0' || c > '9' {
+			numeric = false
+		}
+		if indexByte(qrAlphanumeric, c) < 0 {
+			alphanumeric = false
+		}
+	}
+
+	var bits bitBuffer
+	switch {
+	case numeric:
+		for i := 0; i < len(data); i += 3 {
+			n := min(3, len(data)-i)
+			v := 0
+			for _, c := range data[i : i+n] {
+				v = v*10 + int(c-'0')
+			}
+			bits.append(v, n*3+1)
+		}
+		return qrModeNumeric, bits
+	case alphanumeric:
+		for i := 0; i+1 < len(data); i += 2 {
+			bits.append(indexByte(qrAlphanumeric, data[i])*45+indexByte(qrAlphanumeric, data[i+1]), 11)
+		}
+		if len(data)%2 == 1 {
+			bits.append(indexByte(qrAlphanumeric, data[len(data)-1]), 6)
+		}
+		return qrModeAlphanumeric, bits
+	}
+
+	for _, c := range data {
+		bits.append(int(c), 8)
+	}
+
+	return qrModeByte, bits
+}
+
+func indexByte(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// qrRawModules returns the number of modules available for data and error
+// correction in a symbol of the given version.
+func qrRawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+
+	return n
+}
+
+func qrDataCodewords(version int) int {
+	return qrRawModules(version)/8 - qrECCPerBlockM[version]*qrBlocksM[version]
+}
+
+func encodeQR(data []byte) (*Matrix, error) {
+	mode, payload := qrSegment(data)
+
+	version := 1
+	for ; version <= 40; version++ {
+		if mode.charCountBits(version) < 16 && len(data) >= 1<<mode.charCountBits(version) {
+			continue
+		}
+		if 4+mode.charCountBits(version)+len(payload) <= qrDataCodewords(version)*8 {
+			break
+		}
+	}
+
+	if version > 40 {
+		return nil, fmt.Errorf("Message of %d bytes does not fit in a QR code", len(data))
+	}
+
+	capacity := qrDataCodewords(version) * 8
+
+	var bits bitBuffer
+	bits.append(mode.indicator, 4)
+	bits.append(len(data), mode.charCountBits(version))
+	bits = append(bits, payload...)
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+
+	codewords := make([]int, 0, capacity/8)
+	for i := 0; i < len(bits); i += 8 {
+		v := 0
+		for _, bit := range bits[i : i+8] {
+			v <<= 1
+			if bit {
+				v |= 1
+			}
+		}
+		codewords = append(codewords, v)
+	}
+
+	for pad := 0xec; len(codewords) < capacity/8; pad ^= 0xec ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+
+	q := newQRSymbol(version)
+	q.drawFunctionPatterns()
+	q.drawCodewords(qrInterleave(version, codewords))
+
+	best, bestPenalty := -1, 0
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if p := q.penalty(); best < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		q.applyMask(mask)
+	}
+
+	q.applyMask(best)
+	q.drawFormatBits(best)
+
+	return q.matrix(), nil
+}
+
+// qrInterleave splits the data into blocks, adds error correction to each
+// and interleaves the result.
+func qrInterleave(version int, data []int) []int {
+	numBlocks := qrBlocksM[version]
+	eccLen := qrECCPerBlockM[version]
+	raw := qrRawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	blocks := make([][]int, numBlocks)
+	eccs := make([][]int, numBlocks)
+	k := 0
+	for i := range blocks {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		blocks[i] = data[k : k+n]
+		eccs[i] = qrField.ecc(blocks[i], eccLen)
+		k += n
+	}
+
+	out := make([]int, 0, raw)
+	for i := 0; i <= shortLen-eccLen; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+
+	for i := 0; i < eccLen; i++ {
+		for _, ecc := range eccs {
+			out = append(out, ecc[i])
+		}
+	}
+
+	return out
+}
+
+type qrSymbol struct {
+	version  int
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+func newQRSymbol(version int) *qrSymbol {
+	size := version*4 + 17
+	q := &qrSymbol{version: version, size: size}
+	q.modules = make([][]bool, size)
+	q.function = make([][]bool, size)
+	for i := range q.modules {
+		q.modules[i] = make([]bool, size)
+		q.function[i] = make([]bool, size)
+	}
+
+	return q
+}
+
+func (q *qrSymbol) set(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+func (q *qrSymbol) drawFunctionPatterns() {
+	for i := 0; i < q.size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+
+	q.drawFinder(3, 3)
+	q.drawFinder(q.size-4, 3)
+	q.drawFinder(3, q.size-4)
+
+	pos := q.alignmentPositions()
+	last := len(pos) - 1
+	for i := range pos {
+		for j := range pos {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			q.drawAlignment(pos[i], pos[j])
+		}
+	}
+
+	// Reserve the format areas so data is not placed there.
+	q.drawFormatBits(0)
+	q.drawVersion()
+}
+
+func (q *qrSymbol) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= q.size || yy < 0 || yy >= q.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			q.set(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (q *qrSymbol) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+func (q *qrSymbol) alignmentPositions() []int {
+	if q.version == 1 {
+		return nil
+	}
+
+	n := q.version/7 + 2
+	step := 26
+	if q.version != 32 {
+		step = (q.version*4 + n*2 + 1) / (n*2 - 2) * 2
+	}
+
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, q.size-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+
+	return pos
+}
+
+func (q *qrSymbol) drawFormatBits(mask int) {
+	// Level M is encoded as 00.
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.set(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, q.size-15+i, bit(i))
+	}
+	q.set(8, q.size-8, true)
+}
+
+func (q *qrSymbol) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+
+	rem := q.version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1f25
+	}
+	bits := q.version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 == 1
+		a, b := q.size-11+i%3, i/3
+		q.set(a, b, dark)
+		q.set(b, a, dark)
+	}
+}
+
+func (q *qrSymbol) drawCodewords(data []int) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] && i < len(data)*8 {
+					q.modules[y][x] = data[i>>3]>>(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (q *qrSymbol) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.function[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the four mask evaluation rules of
+// ISO/IEC 18004.
+func (q *qrSymbol) penalty() int {
+	score := 0
+	get := func(x, y int, transpose bool) bool {
+		if transpose {
+			return q.modules[x][y]
+		}
+		return q.modules[y][x]
+	}
+
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < q.size; y++ {
+			run := 0
+			for x := 0; x < q.size; x++ {
+				if x > 0 && get(x, y, transpose) == get(x-1, y, transpose) {
+					run++
+				} else {
+					run = 1
+				}
+				if run == 5 {
+					score += 3
+				} else if run > 5 {
+					score++
+				}
+			}
+
+			for x := 0; x+10 < q.size; x++ {
+				if q.finderLike(x, y, transpose, get) {
+					score += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				c := q.modules[y][x]
+				if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+
+	total := q.size * q.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	score += k * 10
+
+	return score
+}
+
+var (
+	qrFinderBefore = []bool{false, false, false, false, true, false, true, true, true, false, true}
+	qrFinderAfter  = []bool{true, false, true, true, true, false, true, false, false, false, false}
+)
+
+func (q *qrSymbol) finderLike(x, y int, transpose bool, get func(x, y int, transpose bool) bool) bool {
+	for _, pattern := range [][]bool{qrFinderBefore, qrFinderAfter} {
+		match := true
+		for i, dark := range pattern {
+			if get(x+i, y, transpose) != dark {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (q *qrSymbol) matrix() *Matrix {
+	m := NewMatrix(q.size, q.size)
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			m.Set(x, y, q.modules[y][x])
+		}
+	}
+
+	return m
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
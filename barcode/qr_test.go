@@ -0,0 +1,333 @@
+package barcode
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestQRFieldECC(t *testing.T) {
+	tests := []struct {
+		name string
+		data []int
+		ecc  []int
+	}{
+		{
+			// ISO/IEC 18004 Annex I, "01234567" as a 1-M symbol.
+			name: "01234567",
+			data: []int{16, 32, 12, 86, 97, 128, 236, 17, 236, 17, 236, 17, 236, 17, 236, 17},
+			ecc:  []int{165, 36, 212, 193, 237, 54, 199, 135, 44, 85},
+		},
+		{
+			name: "HELLO WORLD",
+			data: []int{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			ecc:  []int{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := qrField.ecc(tt.data, len(tt.ecc)); !slices.Equal(got, tt.ecc) {
+				t.Errorf("ecc = %v, want %v", got, tt.ecc)
+			}
+		})
+	}
+}
+
+// qrFormatBits reads both copies of the format information of m.
+func qrFormatBits(m *Matrix) (int, int) {
+	size := m.Width()
+	read := func(coords [][2]int) int {
+		v := 0
+		for i, c := range coords {
+			if m.Get(c[0], c[1]) {
+				v |= 1 << i
+			}
+		}
+		return v
+	}
+
+	var first, second [][2]int
+	for i := 0; i <= 5; i++ {
+		first = append(first, [2]int{8, i})
+	}
+	first = append(first, [2]int{8, 7}, [2]int{8, 8}, [2]int{7, 8})
+	for i := 9; i < 15; i++ {
+		first = append(first, [2]int{14 - i, 8})
+	}
+
+	for i := 0; i < 8; i++ {
+		second = append(second, [2]int{size - 1 - i, 8})
+	}
+	for i := 8; i < 15; i++ {
+		second = append(second, [2]int{8, size - 15 + i})
+	}
+
+	return read(first), read(second)
+}
+
+// decodeQR reads the data codewords back out of a level M symbol, checking
+// the format information and the error correction of every block.
+func decodeQR(t *testing.T, m *Matrix) (int, []int) {
+	t.Helper()
+
+	size := m.Width()
+	if m.Height() != size || (size-17)%4 != 0 {
+		t.Fatalf("symbol is %dx%d", m.Width(), m.Height())
+	}
+	version := (size - 17) / 4
+
+	first, second := qrFormatBits(m)
+	if first != second {
+		t.Fatalf("format copies differ: %015b and %015b", first, second)
+	}
+
+	format := first ^ 0x5412
+	rem := format >> 10
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	if rem != format&0x3ff {
+		t.Fatalf("format %015b fails the BCH check", first)
+	}
+	if level := format >> 13; level != 0 {
+		t.Fatalf("error correction level bits = %02b, want 00 for M", level)
+	}
+	mask := format >> 10 & 7
+
+	q := newQRSymbol(version)
+	q.drawFunctionPatterns()
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if q.function[y][x] && !(x == 8 || y == 8) && q.modules[y][x] != m.Get(x, y) {
+				t.Fatalf("function module (%d, %d) = %v, want %v", x, y, m.Get(x, y), q.modules[y][x])
+			}
+			if !q.function[y][x] {
+				q.modules[y][x] = m.Get(x, y)
+			}
+		}
+	}
+	q.applyMask(mask)
+
+	var codewords []int
+	var cur, n int
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if q.function[y][x] {
+					continue
+				}
+				cur <<= 1
+				if q.modules[y][x] {
+					cur |= 1
+				}
+				if n++; n == 8 {
+					codewords = append(codewords, cur)
+					cur, n = 0, 0
+				}
+			}
+		}
+	}
+
+	raw := qrRawModules(version) / 8
+	if len(codewords) != raw {
+		t.Fatalf("read %d codewords, want %d", len(codewords), raw)
+	}
+
+	numBlocks, eccLen := qrBlocksM[version], qrECCPerBlockM[version]
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+	blocks := make([][]int, numBlocks)
+	k := 0
+	for i := 0; i < shortLen-eccLen+1; i++ {
+		for b := range blocks {
+			if i < shortLen-eccLen || b >= numShort {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[k])
+			k++
+		}
+	}
+
+	var data []int
+	for b, block := range blocks {
+		n := len(block) - eccLen
+		if got, want := block[n:], qrField.ecc(block[:n], eccLen); !slices.Equal(got, want) {
+			t.Fatalf("block %d error correction = %v, want %v", b, got, want)
+		}
+		data = append(data, block[:n]...)
+	}
+
+	return version, data
+}
+
+// qrMessage parses the single segment the encoder writes out of the data
+// codewords.
+func qrMessage(t *testing.T, version int, data []int) string {
+	t.Helper()
+
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v = v<<1 | data[pos/8]>>(7-pos%8)&1
+			pos++
+		}
+		return v
+	}
+
+	var out strings.Builder
+	switch indicator := read(4); indicator {
+	case qrModeNumeric.indicator:
+		count := read(qrModeNumeric.charCountBits(version))
+		for ; count > 0; count -= 3 {
+			n := min(count, 3)
+			fmt.Fprintf(&out, "%0*d", n, read(n*3+1))
+		}
+	case qrModeAlphanumeric.indicator:
+		count := read(qrModeAlphanumeric.charCountBits(version))
+		for ; count >= 2; count -= 2 {
+			v := read(11)
+			out.WriteByte(qrAlphanumeric[v/45])
+			out.WriteByte(qrAlphanumeric[v%45])
+		}
+		if count == 1 {
+			out.WriteByte(qrAlphanumeric[read(6)])
+		}
+	case qrModeByte.indicator:
+		count := read(qrModeByte.charCountBits(version))
+		for i := 0; i < count; i++ {
+			out.WriteByte(byte(read(8)))
+		}
+	default:
+		t.Fatalf("unexpected mode indicator %04b", indicator)
+	}
+
+	return out.String()
+}
+
+func TestEncodeQR(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		version int
+		data    []int
+	}{
+		{
+			name:    "numeric",
+			message: "01234567",
+			version: 1,
+			data:    []int{16, 32, 12, 86, 97, 128, 236, 17, 236, 17, 236, 17, 236, 17, 236, 17},
+		},
+		{
+			name:    "alphanumeric",
+			message: "HELLO WORLD",
+			version: 1,
+			data:    []int{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+		},
+		{
+			name:    "byte",
+			message: "https://example.com/passes/pass.com.example/ABC123",
+			version: 4,
+		},
+		{
+			// Version 7 and up carry version information and several blocks.
+			name:    "long",
+			message: strings.Repeat("pass.com.example ", 20),
+			version: 14,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := encodeQR([]byte(tt.message))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			version, data := decodeQR(t, m)
+			if version != tt.version {
+				t.Errorf("version = %d, want %d", version, tt.version)
+			}
+			if tt.data != nil && !slices.Equal(data, tt.data) {
+				t.Errorf("data codewords = %v, want %v", data, tt.data)
+			}
+			if got := qrMessage(t, version, data); got != tt.message {
+				t.Errorf("message = %q, want %q", got, tt.message)
+			}
+		})
+	}
+}
+
+func TestEncodeQRFinderPatterns(t *testing.T) {
+	m, err := encodeQR([]byte("HELLO WORLD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each finder is a dark 7x7 ring around a 3x3 core, separated from the
+	// data by a light border.
+	want := []string{
+		"#######.",
+		"#.....#.",
+		"#.###.#.",
+		"#.###.#.",
+		"#.###.#.",
+		"#.....#.",
+		"#######.",
+		"........",
+	}
+
+	size := m.Width()
+	for _, corner := range [][2]int{{0, 0}, {size - 8, 0}, {0, size - 8}} {
+		var rows []string
+		for y := 0; y < 8; y++ {
+			var row bytes.Buffer
+			for x := 0; x < 8; x++ {
+				// Mirror so the light border is always at index 7.
+				xx, yy := x, y
+				if corner[0] > 0 {
+					xx = 7 - x
+				}
+				if corner[1] > 0 {
+					yy = 7 - y
+				}
+				if m.Get(corner[0]+xx, corner[1]+yy) {
+					row.WriteByte('#')
+				} else {
+					row.WriteByte('.')
+				}
+			}
+			rows = append(rows, row.String())
+		}
+		if !slices.Equal(rows, want) {
+			t.Errorf("finder at %v =\n%s", corner, strings.Join(rows, "\n"))
+		}
+	}
+
+	for i := 8; i < size-8; i++ {
+		if m.Get(i, 6) != (i%2 == 0) || m.Get(6, i) != (i%2 == 0) {
+			t.Errorf("timing module %d is wrong", i)
+		}
+	}
+}
+
+func TestEncodeQRTooLong(t *testing.T) {
+	if _, err := encodeQR(bytes.Repeat([]byte{0xff}, 2400)); err == nil {
+		t.Error("expected an error for a message over the version 40 capacity")
+	}
+}
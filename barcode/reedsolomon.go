@@ -0,0 +1,117 @@
+package barcode
+
+// galoisField is GF(2^m) built from a primitive polynomial. base is the
+// exponent of the first root of the generator polynomial, 0 for QR and 1 for
+// Aztec.
+type galoisField struct {
+	size int
+	base int
+	exp  []int
+	log  []int
+}
+
+func newGaloisField(primitive, size, base int) *galoisField {
+	f := &galoisField{size: size, base: base, exp: make([]int, size), log: make([]int, size)}
+
+	x := 1
+	for i := 0; i < size; i++ {
+		f.exp[i] = x
+		x <<= 1
+		if x >= size {
+			x ^= primitive
+		}
+	}
+
+	for i := 0; i < size-1; i++ {
+		f.log[f.exp[i]] = i
+	}
+
+	return f
+}
+
+var (
+	qrField      = newGaloisField(0x11d, 256, 0)
+	aztecField4  = newGaloisField(0x13, 16, 1)
+	aztecField6  = newGaloisField(0x43, 64, 1)
+	aztecField8  = newGaloisField(0x12d, 256, 1)
+	aztecField10 = newGaloisField(0x409, 1024, 1)
+	aztecField12 = newGaloisField(0x1069, 4096, 1)
+)
+
+const pdf417Modulus = 929
+
+func (f *galoisField) mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return f.exp[(f.log[a]+f.log[b])%(f.size-1)]
+}
+
+// generator returns the coefficients of the generator polynomial of degree
+// n, highest degree first.
+func (f *galoisField) generator(n int) []int {
+	gen := []int{1}
+	for i := 0; i < n; i++ {
+		root := f.exp[(f.base+i)%(f.size-1)]
+		next := append(append([]int{}, gen...), 0)
+		for j := len(next) - 1; j > 0; j-- {
+			next[j] ^= f.mul(gen[j-1], root)
+		}
+		gen = next
+	}
+
+	return gen
+}
+
+// ecc returns n Reed-Solomon check words for data.
+func (f *galoisField) ecc(data []int, n int) []int {
+	gen := f.generator(n)
+	rem := make([]int, n)
+
+	for _, d := range data {
+		factor := d ^ rem[0]
+		copy(rem, rem[1:])
+		rem[n-1] = 0
+		for i := 0; i < n; i++ {
+			rem[i] ^= f.mul(gen[i+1], factor)
+		}
+	}
+
+	return rem
+}
+
+// pdf417ECC returns the n error correction codewords for data, computed over
+// the prime field GF(929) as ISO/IEC 15438 describes.
+func pdf417ECC(data []int, n int) []int {
+	m := pdf417Modulus
+
+	// Coefficients of (x - 3)(x - 3^2)...(x - 3^n), lowest degree first.
+	coef := []int{1}
+	root := 1
+	for i := 0; i < n; i++ {
+		root = root * 3 % m
+		next := make([]int, len(coef)+1)
+		for j, c := range coef {
+			next[j+1] = (next[j+1] + c) % m
+			next[j] = (next[j] + m - c*root%m) % m
+		}
+		coef = next
+	}
+
+	ec := make([]int, n)
+	for _, d := range data {
+		t := (d + ec[n-1]) % m
+		for j := n - 1; j > 0; j-- {
+			ec[j] = (ec[j-1] + m - t*coef[j]%m) % m
+		}
+		ec[0] = (m - t*coef[0]%m) % m
+	}
+
+	out := make([]int, n)
+	for j := 0; j < n; j++ {
+		out[j] = (m - ec[n-1-j]) % m
+	}
+
+	return out
+}
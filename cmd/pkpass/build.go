@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+func writeArchive(out string, files map[string][]byte, signer passkit.Signer) error {
+	var buf bytes.Buffer
+	if err := passkit.WriteSignedArchive(&buf, files, sha1.New, signer); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s (%d bytes, %d files)\n", out, buf.Len(), len(files)+2)
+
+	return nil
+}
+
+// archivePath returns the default output of a pass folder or pass.json:
+// <name>.pkpass next to it. The name comes from the absolute path, so that
+// "." names the current folder.
+func archivePath(path string, isDir bool) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	if !isDir {
+		abs = strings.TrimSuffix(abs, filepath.Ext(abs))
+	}
+
+	return abs + ".pkpass", nil
+}
+
+func runBuild(args []string) error {
+	fs := newFlagSet("build")
+	var sf signerFlags
+	sf.register(fs)
+	out := fs.String("o", "", "output `file` (defaults to DIR.pkpass)")
+	noLint := fs.Bool("no-lint", false, "build even if the pass has problems")
+
+	dir, err := oneArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	files, err := readDir(dir)
+	if err != nil {
+		return err
+	}
+
+	if !*noLint && !lintFiles(files) {
+		fmt.Fprintln(os.Stderr, "fix the problems above or pass -no-lint")
+		return errProblems
+	}
+
+	signer, err := sf.signer()
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		if *out, err = archivePath(dir, true); err != nil {
+			return err
+		}
+	}
+
+	return writeArchive(*out, files, signer)
+}
+
+func runSign(args []string) error {
+	fs := newFlagSet("sign")
+	var sf signerFlags
+	sf.register(fs)
+	out := fs.String("o", "", "output `file` (defaults to replacing an archive, or NAME.pkpass for a folder or pass.json)")
+
+	path, err := oneArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	files, err := readPass(path)
+	if err != nil {
+		return err
+	}
+	delete(files, "manifest.json")
+	delete(files, "signature")
+
+	if _, ok := files["pass.json"]; !ok {
+		return fmt.Errorf("%s has no pass.json", path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	isArchive := !info.IsDir() && filepath.Ext(path) != ".json"
+
+	if *out == "" {
+		*out = path
+		if !isArchive {
+			if *out, err = archivePath(path, info.IsDir()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if outInfo, err := os.Stat(*out); err == nil && !isArchive && os.SameFile(info, outInfo) {
+		return fmt.Errorf("%s is not an archive, choose another output with -o", path)
+	}
+
+	signer, err := sf.signer()
+	if err != nil {
+		return err
+	}
+
+	return writeArchive(*out, files, signer)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+var oidUserID = asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 1}
+
+// certIdentity returns the pass type identifier and team identifier a Pass
+// Type ID certificate was issued for.
+func certIdentity(cert *x509.Certificate) (passTypeID, teamID string) {
+	for _, name := range cert.Subject.Names {
+		if name.Type.Equal(oidUserID) {
+			passTypeID, _ = name.Value.(string)
+		}
+	}
+
+	if len(cert.Subject.OrganizationalUnit) > 0 {
+		teamID = cert.Subject.OrganizationalUnit[0]
+	}
+
+	return passTypeID, teamID
+}
+
+func printCertificate(cert *x509.Certificate) {
+	fmt.Printf("  subject:     %s\n", cert.Subject)
+	fmt.Printf("  issuer:      %s\n", cert.Issuer)
+	fmt.Printf("  serial:      %x\n", cert.SerialNumber)
+	fmt.Printf("  valid:       %s to %s\n", cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+
+	if time.Now().After(cert.NotAfter) {
+		fmt.Println("  warning:     certificate has expired")
+	}
+}
+
+func runInspect(args []string) error {
+	path, err := oneArg(newFlagSet("inspect"), args)
+	if err != nil {
+		return err
+	}
+
+	files, err := readPass(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("files:")
+	for _, name := range sortedNames(files) {
+		fmt.Printf("  %-32s %8d bytes\n", name, len(files[name]))
+	}
+
+	for _, name := range []string{"pass.json", "manifest.json"} {
+		data, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			fmt.Printf("\n%s: invalid JSON: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("\n%s:\n%s\n", name, out.String())
+	}
+
+	if files["manifest.json"] == nil || files["signature"] == nil {
+		fmt.Println("\nsignature: none")
+		return nil
+	}
+
+	certs, err := passkit.VerifySignature(files["manifest.json"], files["signature"], nil)
+	if err != nil {
+		fmt.Printf("\nsignature: %v\n", err)
+		return nil
+	}
+
+	fmt.Println("\nsigning certificate:")
+	printCertificate(certs[0])
+	if passTypeID, teamID := certIdentity(certs[0]); passTypeID != "" || teamID != "" {
+		fmt.Printf("  pass type:   %s\n  team:        %s\n", passTypeID, teamID)
+	}
+
+	for _, c := range certs[1:] {
+		fmt.Println("\nchain certificate:")
+		printCertificate(c)
+	}
+
+	return nil
+}
+
+func runVerify(args []string) error {
+	fs := newFlagSet("verify")
+	rootsFile := fs.String("roots", "", "PEM `file` of trusted root certificates, such as the Apple Root CA")
+
+	path, err := oneArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	files, err := readPass(path)
+	if err != nil {
+		return err
+	}
+
+	var roots *x509.CertPool
+	if *rootsFile != "" {
+		data, err := os.ReadFile(*rootsFile)
+		if err != nil {
+			return err
+		}
+
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(data) {
+			return fmt.Errorf("%s holds no PEM certificates", *rootsFile)
+		}
+	}
+
+	certs, err := passkit.VerifyArchive(files, roots)
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(certs) > 0 && files["pass.json"] != nil {
+		if err := checkIdentity(files["pass.json"], certs[0]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: verification failed:\n%v\n", path, err)
+		return errProblems
+	}
+
+	if roots == nil {
+		fmt.Printf("%s: OK (certificate chain not checked, pass -roots)\n", path)
+	} else {
+		fmt.Printf("%s: OK\n", path)
+	}
+
+	return nil
+}
+
+// checkIdentity checks that the pass names the pass type and team of the
+// certificate that signed it, which Wallet requires.
+func checkIdentity(passJSON []byte, cert *x509.Certificate) error {
+	var p struct {
+		PassTypeIdentifier string `json:"passTypeIdentifier"`
+		TeamIdentifier     string `json:"teamIdentifier"`
+	}
+	if err := json.Unmarshal(passJSON, &p); err != nil {
+		return fmt.Errorf("Invalid pass.json: %w", err)
+	}
+
+	passTypeID, teamID := certIdentity(cert)
+
+	var errs []error
+	if passTypeID != "" && passTypeID != p.PassTypeIdentifier {
+		errs = append(errs, fmt.Errorf("Pass type identifier %q does not match the certificate's %q", p.PassTypeIdentifier, passTypeID))
+	}
+
+	if teamID != "" && teamID != p.TeamIdentifier {
+		errs = append(errs, fmt.Errorf("Team identifier %q does not match the certificate's %q", p.TeamIdentifier, teamID))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sortedNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
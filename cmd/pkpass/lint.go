@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+// lintFiles prints the problems of a pass and reports whether it has none.
+func lintFiles(files map[string][]byte) bool {
+	data, ok := files["pass.json"]
+	if !ok {
+		fmt.Fprintln(os.Stderr, "pass.json: missing")
+		return false
+	}
+
+	ok = true
+	report := func(kind string, err error) {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", kind, line)
+		}
+		ok = false
+	}
+
+	p, err := passkit.DecodePass(data, passkit.DecodeStrict)
+	var decodeErr *passkit.DecodeError
+	switch {
+	case errors.As(err, &decodeErr):
+		for _, issue := range decodeErr.Issues {
+			report("pass.json", issue)
+		}
+
+		// Lint the rest of the pass as Wallet would read it.
+		if p, err = passkit.DecodePass(data, passkit.DecodeLenient); err != nil {
+			return false
+		}
+	case err != nil:
+		report("pass.json", err)
+		return false
+	}
+
+	if err := p.Validate(); err != nil {
+		report("validate", err)
+	}
+
+	// A lone relevantDate is still valid, only ignored from iOS 18 on.
+	if p.RelevantDate != nil && len(p.RelevantDates) == 0 {
+		fmt.Fprintln(os.Stderr, "hint: relevantDate: iOS 18 and later read relevantDates instead, consider adding it")
+	}
+
+	// Only a pass folder or archive carries images.
+	if len(files) > 1 && files["icon.png"] == nil {
+		report("images", errors.New("icon.png is required"))
+	}
+
+	if p.PrefersPosterLayout() {
+		var images []passkit.ImageRole
+		for name := range files {
+			if base, ok := strings.CutSuffix(name, ".png"); ok && !strings.Contains(base, "/") {
+				base, _, _ = strings.Cut(base, "@")
+				images = append(images, passkit.ImageRole(base))
+			}
+		}
+
+		if err := p.PosterLayout(images); err != nil {
+			report("poster", err)
+		}
+	}
+
+	return ok
+}
+
+func runLint(args []string) error {
+	path, err := oneArg(newFlagSet("lint"), args)
+	if err != nil {
+		return err
+	}
+
+	files, err := readPass(path)
+	if err != nil {
+		return err
+	}
+
+	if !lintFiles(files) {
+		return errProblems
+	}
+	fmt.Printf("%s: OK\n", path)
+
+	return nil
+}
@@ -0,0 +1,187 @@
+// Command pkpass builds, signs, inspects, verifies, lints and previews Apple
+// Wallet passes without macOS tooling, and serves them from a local web service
+// for development.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"build", "build -cert file [-key file] [-wwdr file] [-o out.pkpass] [-no-lint] DIR", runBuild},
+		{"sign", "sign -cert file [-key file] [-wwdr file] [-o out.pkpass] PASS", runSign},
+		{"inspect", "inspect PASS", runInspect},
+		{"verify", "verify [-roots file] PASS", runVerify},
+		{"lint", "lint PASS|DIR|pass.json", runLint},
+		{"preview", "preview [-o out.html|out.png] [-side front|back] [-lang xx] PASS", runPreview},
+		{"serve", "serve [-cert file -key file -wwdr file] [-addr host:port] [-prefix path] [-url webServiceURL] [-apns URL] DIR", runServe},
+	}
+}
+
+// errProblems signals that the command reported problems and already printed
+// them.
+var errProblems = errors.New("problems found")
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: pkpass <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  pkpass %s\n", c.usage)
+	}
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+
+		err := c.run(os.Args[2:])
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			os.Exit(2)
+		case errors.Is(err, errProblems):
+			os.Exit(1)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "pkpass %s: %v\n", c.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	usage()
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("pkpass "+name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, c := range commands {
+			if c.name == name {
+				fmt.Fprintf(os.Stderr, "usage: pkpass %s\n", c.usage)
+			}
+		}
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// oneArg parses the flags and returns the single positional argument.
+func oneArg(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return "", flag.ErrHelp
+	}
+
+	return fs.Arg(0), nil
+}
+
+type signerFlags struct {
+	cert, key, wwdr string
+}
+
+func (s *signerFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.cert, "cert", "", "PEM `file` of the Pass Type ID certificate")
+	fs.StringVar(&s.key, "key", "", "PEM `file` of its private key (defaults to -cert)")
+	fs.StringVar(&s.wwdr, "wwdr", "", "PEM `file` of the Apple WWDR intermediate certificate")
+}
+
+func (s *signerFlags) signer() (*passkit.CertSigner, error) {
+	if s.cert == "" {
+		return nil, errors.New("-cert is required to sign")
+	}
+
+	key := s.key
+	if key == "" {
+		key = s.cert
+	}
+
+	return passkit.LoadCertSigner(s.cert, key, s.wwdr)
+}
+
+// readDir reads the files of a pass folder, leaving out hidden files and the
+// generated manifest and signature.
+func readDir(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if strings.HasPrefix(d.Name(), ".") && rel != "." {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() || rel == "manifest.json" || rel == "signature" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[rel] = data
+
+		return nil
+	})
+
+	return files, err
+}
+
+// readPass reads a pass folder, a .pkpass archive or a bare pass.json.
+func readPass(path string) (map[string][]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return readDir(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if filepath.Ext(path) == ".json" {
+		return map[string][]byte{"pass.json": data}, nil
+	}
+
+	return passkit.ReadArchive(data)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+	"github.com/clevtech/apple-wallet-pass/preview"
+)
+
+func runPreview(args []string) error {
+	fs := newFlagSet("preview")
+	out := fs.String("o", "", "output `file`, .html for both sides or .png for one (defaults to PASS.html)")
+	side := fs.String("side", "front", "`side` to draw in a PNG, front or back")
+	lang := fs.String("lang", "", "`language` of the xx.lproj strings and images to use")
+
+	path, err := oneArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	files, err := readPass(path)
+	if err != nil {
+		return err
+	}
+
+	if files["pass.json"] == nil {
+		return fmt.Errorf("%s has no pass.json", path)
+	}
+
+	p, err := passkit.DecodePass(files["pass.json"], passkit.DecodeLenient)
+	if err != nil {
+		return err
+	}
+
+	pv := preview.New(p, files)
+	pv.Language = *lang
+
+	if *out == "" {
+		*out = strings.TrimSuffix(filepath.Clean(path), filepath.Ext(path)) + ".html"
+	}
+
+	var buf bytes.Buffer
+	switch filepath.Ext(*out) {
+	case ".html", ".htm":
+		err = pv.HTML(&buf)
+	case ".png":
+		switch *side {
+		case "front":
+			err = pv.PNG(&buf, preview.Front)
+		case "back":
+			err = pv.PNG(&buf, preview.Back)
+		default:
+			return fmt.Errorf("unknown side %q", *side)
+		}
+	default:
+		return fmt.Errorf("%s: output must end in .html or .png", *out)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", *out)
+
+	return nil
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+// libraryPass is a pass found in the served folder.
+type libraryPass struct {
+	path     string
+	files    map[string][]byte
+	modified time.Time
+	token    string
+}
+
+type passKey struct {
+	passType, serial string
+}
+
+type registration struct {
+	device, pushToken string
+	passKey
+}
+
+// devStore keeps registrations in memory and reads the passes from a folder
+// on every request, so edits show up without a restart.
+type devStore struct {
+	dir           string
+	signer        passkit.Signer
+	webServiceURL string
+
+	mu            sync.Mutex
+	registrations map[registration]bool
+}
+
+func newDevStore(dir string, signer passkit.Signer, webServiceURL string) *devStore {
+	return &devStore{dir: dir, signer: signer, webServiceURL: webServiceURL, registrations: make(map[registration]bool)}
+}
+
+// modTime returns when a file or anything in a folder last changed.
+func modTime(path string) (time.Time, error) {
+	var latest time.Time
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+
+		return nil
+	})
+
+	return latest, err
+}
+
+// library reads every pass folder and .pkpass archive directly inside dir.
+func (s *devStore) library() (map[passKey]*libraryPass, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	passes := make(map[passKey]*libraryPass)
+	for _, e := range entries {
+		path := filepath.Join(s.dir, e.Name())
+		if strings.HasPrefix(e.Name(), ".") || (!e.IsDir() && filepath.Ext(path) != ".pkpass") {
+			continue
+		}
+
+		files, err := readPass(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if files["pass.json"] == nil {
+			continue
+		}
+
+		var p struct {
+			AuthenticationToken string `json:"authenticationToken"`
+			PassTypeIdentifier  string `json:"passTypeIdentifier"`
+			SerialNumber        string `json:"serialNumber"`
+		}
+		if err := json.Unmarshal(files["pass.json"], &p); err != nil {
+			return nil, fmt.Errorf("%s: invalid pass.json: %w", path, err)
+		}
+
+		modified, err := modTime(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key := passKey{p.PassTypeIdentifier, p.SerialNumber}
+		if other, ok := passes[key]; ok {
+			return nil, fmt.Errorf("%s and %s are both pass %s %s", other.path, path, key.passType, key.serial)
+		}
+		passes[key] = &libraryPass{path: path, files: files, modified: modified, token: p.AuthenticationToken}
+	}
+
+	return passes, nil
+}
+
+func (s *devStore) lookup(passType, serial string) (*libraryPass, error) {
+	passes, err := s.library()
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := passes[passKey{passType, serial}]
+	if !ok {
+		return nil, passkit.ErrNotFound
+	}
+
+	return p, nil
+}
+
+func (s *devStore) AuthenticationToken(_ context.Context, passType, serial string) (string, error) {
+	p, err := s.lookup(passType, serial)
+	if err != nil {
+		return "", err
+	}
+
+	return p.token, nil
+}
+
+func (s *devStore) Register(_ context.Context, device, pushToken, passType, serial string) (bool, error) {
+	if _, err := s.lookup(passType, serial); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := passKey{passType, serial}
+	for r := range s.registrations {
+		if r.device == device && r.passKey == key {
+			delete(s.registrations, r)
+			s.registrations[registration{device, pushToken, key}] = true
+			return false, nil
+		}
+	}
+	s.registrations[registration{device, pushToken, key}] = true
+
+	return true, nil
+}
+
+func (s *devStore) Unregister(_ context.Context, device, passType, serial string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for r := range s.registrations {
+		if r.device == device && r.passKey == (passKey{passType, serial}) {
+			delete(s.registrations, r)
+			return nil
+		}
+	}
+
+	return passkit.ErrNotFound
+}
+
+// UpdatedPasses uses the latest modification time, in nanoseconds, as the
+// tag.
+func (s *devStore) UpdatedPasses(_ context.Context, device, passType, updatedSince string) ([]string, string, error) {
+	// A tag this server did not hand out lists every pass.
+	since, _ := strconv.ParseInt(updatedSince, 10, 64)
+
+	passes, err := s.library()
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var serials []string
+	latest := since
+	for r := range s.registrations {
+		p, ok := passes[r.passKey]
+		if r.device != device || r.passType != passType || !ok {
+			continue
+		}
+
+		if modified := p.modified.UnixNano(); modified > since {
+			serials = append(serials, r.serial)
+			latest = max(latest, modified)
+		}
+	}
+	slices.Sort(serials)
+
+	return serials, strconv.FormatInt(latest, 10), nil
+}
+
+func (s *devStore) Pass(_ context.Context, passType, serial string) ([]byte, time.Time, error) {
+	p, err := s.lookup(passType, serial)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	data, err := s.archive(p)
+
+	return data, p.modified, err
+}
+
+// archive signs the pass, pointing it at the dev server first if asked to.
+// Archives are served untouched when there is nothing to change or no
+// certificate to sign them with.
+func (s *devStore) archive(p *libraryPass) ([]byte, error) {
+	signed := p.files["signature"] != nil
+	if signed && (s.webServiceURL == "" || s.signer == nil) {
+		return os.ReadFile(p.path)
+	}
+
+	if s.signer == nil {
+		return nil, fmt.Errorf("%s: -cert is required to serve pass folders", p.path)
+	}
+
+	files := make(map[string][]byte, len(p.files))
+	for name, data := range p.files {
+		if name != "manifest.json" && name != "signature" {
+			files[name] = data
+		}
+	}
+
+	if s.webServiceURL != "" {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(files["pass.json"], &fields); err != nil {
+			return nil, err
+		}
+
+		url, err := json.Marshal(s.webServiceURL)
+		if err != nil {
+			return nil, err
+		}
+		fields["webServiceURL"] = url
+
+		if files["pass.json"], err = json.Marshal(fields); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := passkit.WriteSignedArchive(&buf, files, sha1.New, s.signer); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// pushTokens returns the push tokens registered for a pass.
+func (s *devStore) pushTokens(passType, serial string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tokens []string
+	for r := range s.registrations {
+		if r.passKey == (passKey{passType, serial}) {
+			tokens = append(tokens, r.pushToken)
+		}
+	}
+	slices.Sort(tokens)
+
+	return tokens
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs every call with its outcome.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("%s %s -> %d (%s)", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// apnsStandIn answers pushes like APNs does and logs them instead of
+// delivering them.
+func apnsStandIn(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(io.LimitReader(r.Body, 4<<10))
+
+	reason := ""
+	switch {
+	case r.Header.Get("apns-topic") == "":
+		reason = "MissingTopic"
+	case !json.Valid(body):
+		reason = "PayloadEmpty"
+	}
+
+	if reason != "" {
+		log.Printf("apns: rejected push to %s: %s", r.PathValue("token"), reason)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"reason": reason})
+		return
+	}
+
+	log.Printf("apns: push to %s, topic %s, payload %s", r.PathValue("token"), r.Header.Get("apns-topic"), body)
+}
+
+func runServe(args []string) error {
+	fs := newFlagSet("serve")
+	var sf signerFlags
+	sf.register(fs)
+	addr := fs.String("addr", "localhost:8080", "`address` to listen on")
+	prefix := fs.String("prefix", "/passes", "`path` the web service is served under")
+	webServiceURL := fs.String("url", "", "webServiceURL to put in served passes, such as http://192.168.1.10:8080/passes (requires -cert)")
+	apns := fs.String("apns", "", "push server `URL` (defaults to the built-in stand-in)")
+
+	dir, err := oneArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var signer passkit.Signer
+	if sf.cert != "" {
+		if signer, err = sf.signer(); err != nil {
+			return err
+		}
+	}
+
+	store := newDevStore(dir, signer, *webServiceURL)
+	if _, err := store.library(); err != nil {
+		return err
+	}
+
+	handler := passkit.NewHandler(store)
+	handler.Log = func(messages []string) {
+		for _, m := range messages {
+			log.Printf("device: %s", m)
+		}
+	}
+
+	*prefix = "/" + strings.Trim(*prefix, "/")
+	if *apns == "" {
+		*apns = "http://" + *addr
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(*prefix+"/", http.StripPrefix(*prefix, handler))
+	mux.HandleFunc("POST /3/device/{token}", apnsStandIn)
+
+	// The dev endpoints need no authentication: they hand out passes to add
+	// to a device and trigger pushes for them.
+	mux.HandleFunc("GET /dev/passes/{passType}/{serial}", func(w http.ResponseWriter, r *http.Request) {
+		p, err := store.lookup(r.PathValue("passType"), r.PathValue("serial"))
+		if err != nil {
+			http.Error(w, err.Error(), storeStatus(err))
+			return
+		}
+
+		data, err := store.archive(p)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", passkit.PassContentType)
+		w.Write(data)
+	})
+	mux.HandleFunc("POST /dev/push/{passType}/{serial}", func(w http.ResponseWriter, r *http.Request) {
+		passType := r.PathValue("passType")
+		tokens := store.pushTokens(passType, r.PathValue("serial"))
+		if len(tokens) == 0 {
+			http.Error(w, "no devices registered for this pass", http.StatusNotFound)
+			return
+		}
+
+		var errs []error
+		for _, token := range tokens {
+			if err := passkit.Push(r.Context(), http.DefaultClient, *apns, passType, token); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", token, err))
+			}
+		}
+
+		if err := errors.Join(errs...); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintf(w, "pushed to %d devices\n", len(tokens))
+	})
+
+	log.Printf("serving %s on http://%s%s", dir, *addr, *prefix)
+	log.Printf("download passes from http://%s/dev/passes/{passTypeIdentifier}/{serialNumber}", *addr)
+	log.Printf("push updates with POST http://%s/dev/push/{passTypeIdentifier}/{serialNumber}", *addr)
+
+	return http.ListenAndServe(*addr, logRequests(mux))
+}
+
+func storeStatus(err error) int {
+	if errors.Is(err, passkit.ErrNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
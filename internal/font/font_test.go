@@ -0,0 +1,36 @@
+package font
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDraw(t *testing.T) {
+	if got := TextWidth("Aé", 2); got != 2*Width*2 {
+		t.Errorf("TextWidth = %d, want %d", got, 2*Width*2)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, TextWidth("I", 2), Height*2))
+	Draw(img, 0, 0, "I", 2, color.Black)
+
+	var set int
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			if img.RGBAAt(x, y).A != 0 {
+				set++
+			}
+		}
+	}
+	if set == 0 || set%4 != 0 {
+		t.Errorf("%d pixels set, want a non-zero multiple of 4 for scale 2", set)
+	}
+
+	// Runes outside ASCII are drawn as '?'.
+	a, b := image.NewRGBA(img.Bounds()), image.NewRGBA(img.Bounds())
+	Draw(a, 0, 0, "é", 2, color.Black)
+	Draw(b, 0, 0, "?", 2, color.Black)
+	if string(a.Pix) != string(b.Pix) {
+		t.Error("é is not drawn as ?")
+	}
+}
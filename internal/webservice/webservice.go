@@ -0,0 +1,194 @@
+// Package webservice implements the web service protocol shared by Wallet
+// passes and orders: devices authenticate with the token of an item, register
+// for its updates, fetch it and report errors.
+package webservice
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by a Store for unknown items and registrations.
+var ErrNotFound = errors.New("Not found")
+
+// maxBodySize caps the request bodies the handler decodes.
+const maxBodySize = 1 << 20
+
+// Store is the persistence behind a web service. Items are passes or orders,
+// named by their type identifier and their serial number or identifier.
+type Store interface {
+	AuthenticationToken(ctx context.Context, typeID, id string) (string, error)
+	Register(ctx context.Context, device, pushToken, typeID, id string) (bool, error)
+	Unregister(ctx context.Context, device, typeID, id string) error
+	Updated(ctx context.Context, device, typeID, since string) ([]string, string, error)
+	Item(ctx context.Context, typeID, id string) ([]byte, time.Time, error)
+}
+
+// Protocol holds the names that differ between the pass and the order web
+// service.
+type Protocol struct {
+	// Scheme is the scheme of the Authorization header, such as ApplePass.
+	Scheme string
+
+	// Items is the path segment items are fetched under, such as passes.
+	Items string
+
+	ContentType string
+
+	// SinceParam is the query parameter holding the tag of the last update.
+	SinceParam string
+
+	// IDsKey and TagKey name the identifiers and the next tag in the list
+	// of updated items.
+	IDsKey string
+	TagKey string
+}
+
+type handler struct {
+	proto Protocol
+	store Store
+	log   func(messages []string)
+}
+
+// NewHandler routes the web service requests to store, passing the messages
+// devices log to log.
+func NewHandler(proto Protocol, store Store, log func(messages []string)) http.Handler {
+	h := &handler{proto: proto, store: store, log: log}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/"+proto.Items+"/{type}/{id}", h.get)
+	mux.HandleFunc("POST /v1/devices/{device}/registrations/{type}/{id}", h.register)
+	mux.HandleFunc("DELETE /v1/devices/{device}/registrations/{type}/{id}", h.unregister)
+	mux.HandleFunc("GET /v1/devices/{device}/registrations/{type}", h.updated)
+	mux.HandleFunc("POST /v1/log", h.logMessages)
+
+	return mux
+}
+
+func storeStatus(err error) int {
+	if errors.Is(err, ErrNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+// authorized checks the authorization header against the token of the item,
+// writing the error response if it does not match.
+func (h *handler) authorized(w http.ResponseWriter, r *http.Request) bool {
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), h.proto.Scheme+" ")
+	if !ok || given == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+
+	token, err := h.store.AuthenticationToken(r.Context(), r.PathValue("type"), r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(storeStatus(err))
+		return false
+	}
+
+	// An item without a token can not be authorized.
+	if token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return false
+	}
+
+	return true
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(w, r) {
+		return
+	}
+
+	data, modified, err := h.store.Item(r.Context(), r.PathValue("type"), r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(storeStatus(err))
+		return
+	}
+
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.Truncate(time.Second).After(since) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", h.proto.ContentType)
+	w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	w.Write(data)
+}
+
+func (h *handler) register(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(w, r) {
+		return
+	}
+
+	var body struct {
+		PushToken string `json:"pushToken"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&body); err != nil || body.PushToken == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.store.Register(r.Context(), r.PathValue("device"), body.PushToken, r.PathValue("type"), r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(storeStatus(err))
+		return
+	}
+
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *handler) unregister(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(w, r) {
+		return
+	}
+
+	if err := h.store.Unregister(r.Context(), r.PathValue("device"), r.PathValue("type"), r.PathValue("id")); err != nil {
+		w.WriteHeader(storeStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *handler) updated(w http.ResponseWriter, r *http.Request) {
+	ids, tag, err := h.store.Updated(r.Context(), r.PathValue("device"), r.PathValue("type"), r.URL.Query().Get(h.proto.SinceParam))
+	if err != nil {
+		w.WriteHeader(storeStatus(err))
+		return
+	}
+
+	if len(ids) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{h.proto.IDsKey: ids, h.proto.TagKey: tag})
+}
+
+func (h *handler) logMessages(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Logs []string `json:"logs"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if h.log != nil {
+		h.log(body.Logs)
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,129 @@
+package webservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testModified = time.Date(2024, 6, 20, 12, 0, 0, 500, time.UTC)
+
+type memStore struct {
+	registered map[string]bool
+}
+
+func (s *memStore) AuthenticationToken(ctx context.Context, typeID, id string) (string, error) {
+	if typeID != "pass.com.example" || id != "1" {
+		return "", ErrNotFound
+	}
+
+	return "secret-token-0123", nil
+}
+
+func (s *memStore) Register(ctx context.Context, device, pushToken, typeID, id string) (bool, error) {
+	created := !s.registered[device]
+	s.registered[device] = true
+
+	return created, nil
+}
+
+func (s *memStore) Unregister(ctx context.Context, device, typeID, id string) error {
+	if !s.registered[device] {
+		return ErrNotFound
+	}
+	delete(s.registered, device)
+
+	return nil
+}
+
+func (s *memStore) Updated(ctx context.Context, device, typeID, since string) ([]string, string, error) {
+	if since == "2" {
+		return nil, "", nil
+	}
+
+	return []string{"1"}, "2", nil
+}
+
+func (s *memStore) Item(ctx context.Context, typeID, id string) ([]byte, time.Time, error) {
+	return []byte("archive"), testModified, nil
+}
+
+func TestHandler(t *testing.T) {
+	var logged []string
+	h := NewHandler(Protocol{
+		Scheme:      "ApplePass",
+		Items:       "passes",
+		ContentType: "application/vnd.apple.pkpass",
+		SinceParam:  "passesUpdatedSince",
+		IDsKey:      "serialNumbers",
+		TagKey:      "lastUpdated",
+	}, &memStore{registered: make(map[string]bool)}, func(messages []string) {
+		logged = append(logged, messages...)
+	})
+
+	const auth = "ApplePass secret-token-0123"
+	tests := []struct {
+		name, method, path, auth, body string
+		header                         map[string]string
+		status                         int
+		want                           string
+	}{
+		{name: "no authorization", method: "GET", path: "/v1/passes/pass.com.example/1", status: http.StatusUnauthorized},
+		{name: "wrong scheme", method: "GET", path: "/v1/passes/pass.com.example/1", auth: "AppleOrder secret-token-0123", status: http.StatusUnauthorized},
+		{name: "wrong token", method: "GET", path: "/v1/passes/pass.com.example/1", auth: "ApplePass other", status: http.StatusUnauthorized},
+		{name: "unknown item", method: "GET", path: "/v1/passes/pass.com.example/2", auth: auth, status: http.StatusNotFound},
+		{name: "get", method: "GET", path: "/v1/passes/pass.com.example/1", auth: auth, status: http.StatusOK, want: "archive"},
+		{
+			name: "not modified", method: "GET", path: "/v1/passes/pass.com.example/1", auth: auth,
+			header: map[string]string{"If-Modified-Since": testModified.Format(http.TimeFormat)},
+			status: http.StatusNotModified,
+		},
+		{
+			name: "modified", method: "GET", path: "/v1/passes/pass.com.example/1", auth: auth,
+			header: map[string]string{"If-Modified-Since": testModified.Add(-time.Second).Format(http.TimeFormat)},
+			status: http.StatusOK, want: "archive",
+		},
+		{name: "register", method: "POST", path: "/v1/devices/d1/registrations/pass.com.example/1", auth: auth, body: `{"pushToken":"t"}`, status: http.StatusCreated},
+		{name: "register again", method: "POST", path: "/v1/devices/d1/registrations/pass.com.example/1", auth: auth, body: `{"pushToken":"t"}`, status: http.StatusOK},
+		{name: "register without token", method: "POST", path: "/v1/devices/d1/registrations/pass.com.example/1", auth: auth, body: `{}`, status: http.StatusBadRequest},
+		{
+			name: "register with a large body", method: "POST", path: "/v1/devices/d1/registrations/pass.com.example/1", auth: auth,
+			body:   `{"pushToken":"t","padding":"` + strings.Repeat("x", maxBodySize) + `"}`,
+			status: http.StatusBadRequest,
+		},
+		{name: "updated", method: "GET", path: "/v1/devices/d1/registrations/pass.com.example", status: http.StatusOK, want: `{"lastUpdated":"2","serialNumbers":["1"]}` + "\n"},
+		{name: "nothing updated", method: "GET", path: "/v1/devices/d1/registrations/pass.com.example?passesUpdatedSince=2", status: http.StatusNoContent},
+		{name: "unregister", method: "DELETE", path: "/v1/devices/d1/registrations/pass.com.example/1", auth: auth, status: http.StatusOK},
+		{name: "unregister again", method: "DELETE", path: "/v1/devices/d1/registrations/pass.com.example/1", auth: auth, status: http.StatusNotFound},
+		{name: "log", method: "POST", path: "/v1/log", body: `{"logs":["a","b"]}`, status: http.StatusOK},
+		{name: "log with a bad body", method: "POST", path: "/v1/log", body: `[`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if tt.auth != "" {
+			r.Header.Set("Authorization", tt.auth)
+		}
+		for k, v := range tt.header {
+			r.Header.Set(k, v)
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if tt.want != "" && w.Body.String() != tt.want {
+			t.Errorf("%s: body %q, want %q", tt.name, w.Body, tt.want)
+		}
+	}
+
+	if !reflect.DeepEqual(logged, []string{"a", "b"}) {
+		t.Errorf("logged %q", logged)
+	}
+}
@@ -0,0 +1,29 @@
+package orders
+
+import (
+	"crypto/sha256"
+	"io"
+	"maps"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+const ContentType = "application/vnd.apple.finance.order"
+
+// WriteArchive writes the order as a signed .order archive. Unlike passes,
+// order manifests use SHA-256 digests. files holds the images and
+// localizations to bundle, keyed by their path in the archive.
+func (o *Order) WriteArchive(w io.Writer, files map[string][]byte, signer passkit.Signer) error {
+	data, err := o.ToJson()
+	if err != nil {
+		return err
+	}
+
+	all := maps.Clone(files)
+	if all == nil {
+		all = make(map[string][]byte)
+	}
+	all["order.json"] = data
+
+	return passkit.WriteSignedArchive(w, all, sha256.New, signer)
+}
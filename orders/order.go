@@ -0,0 +1,277 @@
+// Package orders builds Wallet orders: the order.json model, signed .order
+// archives and the web service Wallet uses to keep orders up to date.
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+type BarcodeFormat string
+type FulfillmentType string
+type OrderStatus string
+type OrderType string
+type PaymentStatus string
+type PickupStatus string
+type ShippingStatus string
+type ShippingType string
+
+const (
+	SchemaVersion = 1
+
+	BarcodeFormatAztec   BarcodeFormat = "aztec"
+	BarcodeFormatCode128 BarcodeFormat = "code128"
+	BarcodeFormatPDF417  BarcodeFormat = "pdf417"
+	BarcodeFormatQR      BarcodeFormat = "qr"
+
+	FulfillmentTypePickup   FulfillmentType = "pickup"
+	FulfillmentTypeShipping FulfillmentType = "shipping"
+
+	OrderStatusOpen      OrderStatus = "open"
+	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+
+	OrderTypeEcommerce OrderType = "ecommerce"
+
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusPaid       PaymentStatus = "paid"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+	PaymentStatusCancelled  PaymentStatus = "cancelled"
+
+	PickupStatusOpen           PickupStatus = "open"
+	PickupStatusProcessing     PickupStatus = "processing"
+	PickupStatusReadyForPickup PickupStatus = "readyForPickup"
+	PickupStatusPickedUp       PickupStatus = "pickedUp"
+	PickupStatusCancelled      PickupStatus = "cancelled"
+	PickupStatusIssue          PickupStatus = "issue"
+
+	ShippingStatusProcessing     ShippingStatus = "processing"
+	ShippingStatusShipped        ShippingStatus = "shipped"
+	ShippingStatusOnTheWay       ShippingStatus = "onTheWay"
+	ShippingStatusOutForDelivery ShippingStatus = "outForDelivery"
+	ShippingStatusDelivered      ShippingStatus = "delivered"
+	ShippingStatusIssue          ShippingStatus = "issue"
+	ShippingStatusCancelled      ShippingStatus = "cancelled"
+
+	ShippingTypeShipping ShippingType = "shipping"
+	ShippingTypeDelivery ShippingType = "delivery"
+)
+
+type Order struct {
+	AuthenticationToken string        `json:"authenticationToken,omitempty"`
+	CreatedAt           *passkit.Date `json:"createdAt"`
+	Customer            *Customer     `json:"customer,omitempty"`
+	Fulfillments        []Fulfillment `json:"fulfillments,omitempty"`
+	LineItems           []LineItem    `json:"lineItems,omitempty"`
+	Merchant            *Merchant     `json:"merchant"`
+	OrderIdentifier     string        `json:"orderIdentifier"`
+	OrderManagementURL  string        `json:"orderManagementURL,omitempty"`
+	OrderNumber         string        `json:"orderNumber,omitempty"`
+	OrderType           OrderType     `json:"orderType"`
+	OrderTypeIdentifier string        `json:"orderTypeIdentifier"`
+	Payment             *Payment      `json:"payment,omitempty"`
+	SchemaVersion       int64         `json:"schemaVersion"`
+	Status              OrderStatus   `json:"status"`
+	StatusDescription   string        `json:"statusDescription,omitempty"`
+	UpdatedAt           *passkit.Date `json:"updatedAt"`
+	WebServiceURL       string        `json:"webServiceURL,omitempty"`
+}
+
+func NewOrder(orderTypeIdentifier, orderIdentifier string, merchant *Merchant) *Order {
+	return &Order{
+		Merchant:            merchant,
+		OrderIdentifier:     orderIdentifier,
+		OrderType:           OrderTypeEcommerce,
+		OrderTypeIdentifier: orderTypeIdentifier,
+		SchemaVersion:       SchemaVersion,
+		Status:              OrderStatusOpen,
+	}
+}
+
+func (o *Order) ToJson() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+type Merchant struct {
+	BusinessChatURL    string `json:"businessChatURL,omitempty"`
+	DisplayName        string `json:"displayName"`
+	Logo               string `json:"logo,omitempty"`
+	MerchantIdentifier string `json:"merchantIdentifier"`
+	URL                string `json:"url"`
+}
+
+type Customer struct {
+	EmailAddress     string `json:"emailAddress,omitempty"`
+	FamilyName       string `json:"familyName,omitempty"`
+	GivenName        string `json:"givenName,omitempty"`
+	OrganizationName string `json:"organizationName,omitempty"`
+	PhoneNumber      string `json:"phoneNumber,omitempty"`
+}
+
+// CurrencyAmount is an amount of money. Orders encode the amount as a JSON
+// number, unlike the string amounts of pass semantic tags; json.Number keeps
+// its decimal digits exact and still reads amounts written as strings.
+type CurrencyAmount struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+type LineItem struct {
+	GTIN     string          `json:"gtin,omitempty"`
+	Image    string          `json:"image,omitempty"`
+	Price    *CurrencyAmount `json:"price,omitempty"`
+	Quantity float64         `json:"quantity"`
+	SKU      string          `json:"sku,omitempty"`
+	Subtitle string          `json:"subtitle,omitempty"`
+	Title    string          `json:"title"`
+}
+
+type Payment struct {
+	PaymentMethods []PaymentMethod `json:"paymentMethods,omitempty"`
+	Status         PaymentStatus   `json:"status"`
+	SummaryItems   []SummaryItem   `json:"summaryItems,omitempty"`
+	Total          *CurrencyAmount `json:"total"`
+}
+
+type PaymentMethod struct {
+	DisplayName string `json:"displayName"`
+}
+
+type SummaryItem struct {
+	Label string          `json:"label"`
+	Value *CurrencyAmount `json:"value"`
+}
+
+type Address struct {
+	City                  string   `json:"city,omitempty"`
+	Country               string   `json:"country,omitempty"`
+	ISOCountryCode        string   `json:"ISOCountryCode,omitempty"`
+	PostalCode            string   `json:"postalCode,omitempty"`
+	State                 string   `json:"state,omitempty"`
+	Street                []string `json:"street,omitempty"`
+	SubAdministrativeArea string   `json:"subAdministrativeArea,omitempty"`
+	SubLocality           string   `json:"subLocality,omitempty"`
+}
+
+type Location struct {
+	Coordinate    *passkit.Location `json:"coordinate,omitempty"`
+	DisplayName   string            `json:"displayName"`
+	PostalAddress *Address          `json:"postalAddress,omitempty"`
+}
+
+type Recipient struct {
+	DisplayName   string   `json:"displayName,omitempty"`
+	EmailAddress  string   `json:"emailAddress,omitempty"`
+	PhoneNumber   string   `json:"phoneNumber,omitempty"`
+	PostalAddress *Address `json:"postalAddress,omitempty"`
+}
+
+type FulfillmentLineItem struct {
+	Image    string  `json:"image,omitempty"`
+	Quantity float64 `json:"quantity"`
+	Title    string  `json:"title"`
+}
+
+type ShippingFulfillment struct {
+	Carrier               string                `json:"carrier,omitempty"`
+	DeliveredAt           *passkit.Date         `json:"deliveredAt,omitempty"`
+	EstimatedDeliveryAt   *passkit.Date         `json:"estimatedDeliveryAt,omitempty"`
+	FulfillmentIdentifier string                `json:"fulfillmentIdentifier"`
+	LineItems             []FulfillmentLineItem `json:"lineItems,omitempty"`
+	Notes                 string                `json:"notes,omitempty"`
+	Recipient             *Recipient            `json:"recipient,omitempty"`
+	ShippedAt             *passkit.Date         `json:"shippedAt,omitempty"`
+	ShippingType          ShippingType          `json:"shippingType,omitempty"`
+	Status                ShippingStatus        `json:"status"`
+	StatusDescription     string                `json:"statusDescription,omitempty"`
+	TrackingNumber        string                `json:"trackingNumber,omitempty"`
+	TrackingURL           string                `json:"trackingURL,omitempty"`
+}
+
+type PickupWindow struct {
+	EndDate   *passkit.Date `json:"endDate"`
+	StartDate *passkit.Date `json:"startDate"`
+}
+
+// Barcode is the code shown for a pickup. Its format names are the lower case
+// ones of the order schema, not the PKBarcodeFormat names passes use.
+type Barcode struct {
+	AltText         string        `json:"altText,omitempty"`
+	Format          BarcodeFormat `json:"format"`
+	Message         string        `json:"message"`
+	MessageEncoding string        `json:"messageEncoding"`
+}
+
+type PickupFulfillment struct {
+	Barcode               *Barcode              `json:"barcode,omitempty"`
+	FulfillmentIdentifier string                `json:"fulfillmentIdentifier"`
+	Instructions          string                `json:"instructions,omitempty"`
+	LineItems             []FulfillmentLineItem `json:"lineItems,omitempty"`
+	Location              *Location             `json:"location,omitempty"`
+	Notes                 string                `json:"notes,omitempty"`
+	PickedUpAt            *passkit.Date         `json:"pickedUpAt,omitempty"`
+	PickupAt              *passkit.Date         `json:"pickupAt,omitempty"`
+	PickupWindow          *PickupWindow         `json:"pickupWindow,omitempty"`
+	Status                PickupStatus          `json:"status"`
+	StatusDescription     string                `json:"statusDescription,omitempty"`
+}
+
+// Fulfillment is either a shipping or a pickup fulfillment, told apart by
+// the fulfillmentType key in JSON.
+type Fulfillment struct {
+	Pickup   *PickupFulfillment
+	Shipping *ShippingFulfillment
+}
+
+func (f Fulfillment) Type() FulfillmentType {
+	switch {
+	case f.Shipping != nil:
+		return FulfillmentTypeShipping
+	case f.Pickup != nil:
+		return FulfillmentTypePickup
+	}
+
+	return ""
+}
+
+func (f Fulfillment) MarshalJSON() ([]byte, error) {
+	switch {
+	case f.Shipping != nil:
+		return json.Marshal(struct {
+			FulfillmentType FulfillmentType `json:"fulfillmentType"`
+			*ShippingFulfillment
+		}{FulfillmentTypeShipping, f.Shipping})
+	case f.Pickup != nil:
+		return json.Marshal(struct {
+			FulfillmentType FulfillmentType `json:"fulfillmentType"`
+			*PickupFulfillment
+		}{FulfillmentTypePickup, f.Pickup})
+	}
+
+	return nil, errors.New("Fulfillment can not be empty")
+}
+
+func (f *Fulfillment) UnmarshalJSON(data []byte) error {
+	var head struct {
+		FulfillmentType FulfillmentType `json:"fulfillmentType"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+
+	*f = Fulfillment{}
+	switch head.FulfillmentType {
+	case FulfillmentTypeShipping:
+		f.Shipping = &ShippingFulfillment{}
+		return json.Unmarshal(data, f.Shipping)
+	case FulfillmentTypePickup:
+		f.Pickup = &PickupFulfillment{}
+		return json.Unmarshal(data, f.Pickup)
+	}
+
+	return fmt.Errorf("Unknown fulfillment type %q", head.FulfillmentType)
+}
@@ -0,0 +1,80 @@
+package orders
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCurrencyAmountJSON(t *testing.T) {
+	data, err := json.Marshal(CurrencyAmount{Amount: "19.90", Currency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"amount":19.90,"currency":"USD"}`; string(data) != want {
+		t.Errorf("encoded %s, want %s", data, want)
+	}
+
+	for _, in := range []string{`{"amount":19.90,"currency":"USD"}`, `{"amount":"19.90","currency":"USD"}`} {
+		var c CurrencyAmount
+		if err := json.Unmarshal([]byte(in), &c); err != nil {
+			t.Fatalf("decoding %s: %v", in, err)
+		}
+		if c.Amount != "19.90" {
+			t.Errorf("decoding %s: amount = %s, want 19.90", in, c.Amount)
+		}
+	}
+
+	if err := (&CurrencyAmount{Amount: "1e3", Currency: "USD"}).Validate(); err == nil {
+		t.Error("expected an error for an amount in exponent form")
+	}
+}
+
+func TestPickupBarcode(t *testing.T) {
+	f := Fulfillment{Pickup: &PickupFulfillment{
+		FulfillmentIdentifier: "pickup-1",
+		Status:                PickupStatusReadyForPickup,
+		Barcode:               &Barcode{Format: BarcodeFormatQR, Message: "ORDER-123", MessageEncoding: "iso-8859-1"},
+	}}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"barcode":{"format":"qr","message":"ORDER-123","messageEncoding":"iso-8859-1"}`) {
+		t.Errorf("encoded %s", data)
+	}
+
+	var decoded Fulfillment
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Pickup == nil || decoded.Pickup.Barcode == nil || decoded.Pickup.Barcode.Format != BarcodeFormatQR {
+		t.Fatalf("decoded %+v", decoded.Pickup)
+	}
+
+	tests := []struct {
+		format BarcodeFormat
+		want   string
+	}{
+		{format: BarcodeFormatAztec},
+		{format: BarcodeFormatCode128},
+		{format: BarcodeFormatPDF417},
+		{format: BarcodeFormatQR},
+		{format: "PKBarcodeFormatQR", want: `Unknown barcode format "PKBarcodeFormatQR"`},
+		{format: "", want: "Barcode format can not be empty"},
+	}
+
+	for _, tt := range tests {
+		b := &Barcode{Format: tt.format, Message: "ORDER-123", MessageEncoding: "iso-8859-1"}
+		err := b.Validate()
+		if tt.want == "" && err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.format, err)
+		}
+		if tt.want != "" && (err == nil || err.Error() != tt.want) {
+			t.Errorf("%q: error = %v, want %q", tt.format, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+// MinAuthenticationTokenLength is the shortest token Wallet accepts for the
+// order web service.
+const MinAuthenticationTokenLength = 16
+
+func (c *CurrencyAmount) Validate() error {
+	var errs []error
+
+	if !passkit.IsValidAmount(string(c.Amount)) {
+		errs = append(errs, fmt.Errorf("Amount %q is not a decimal number", c.Amount))
+	}
+
+	if !passkit.IsValidCurrencyCode(c.Currency) {
+		errs = append(errs, fmt.Errorf("Unknown ISO 4217 currency code %q", c.Currency))
+	}
+
+	return errors.Join(errs...)
+}
+
+var barcodeFormats = map[BarcodeFormat]passkit.BarcodeFormat{
+	BarcodeFormatAztec:   passkit.BarcodeFormatAztec,
+	BarcodeFormatCode128: passkit.BarcodeFormatCode128,
+	BarcodeFormatPDF417:  passkit.BarcodeFormatPDF417,
+	BarcodeFormatQR:      passkit.BarcodeFormatQR,
+}
+
+// PassBarcode returns the barcode as its pass equivalent, which the barcode
+// package can render.
+func (b *Barcode) PassBarcode() (passkit.Barcodes, error) {
+	format, ok := barcodeFormats[b.Format]
+	if !ok {
+		if b.Format == "" {
+			return passkit.Barcodes{}, errors.New("Barcode format can not be empty")
+		}
+
+		return passkit.Barcodes{}, fmt.Errorf("Unknown barcode format %q", b.Format)
+	}
+
+	return passkit.Barcodes{AltText: b.AltText, Format: format, Message: b.Message, MessageEncoding: b.MessageEncoding}, nil
+}
+
+func (b *Barcode) Validate() error {
+	pb, err := b.PassBarcode()
+	if err != nil {
+		return err
+	}
+
+	return pb.Validate()
+}
+
+func (m *Merchant) Validate() error {
+	var errs []error
+
+	if m.MerchantIdentifier == "" {
+		errs = append(errs, errors.New("Merchant identifier can not be empty"))
+	}
+
+	if m.DisplayName == "" {
+		errs = append(errs, errors.New("Merchant display name can not be empty"))
+	}
+
+	if !passkit.IsHTTPSURL(m.URL) {
+		errs = append(errs, fmt.Errorf("Merchant URL %q must be an https URL", m.URL))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Payment) Validate() error {
+	var errs []error
+
+	switch p.Status {
+	case PaymentStatusPending, PaymentStatusAuthorized, PaymentStatusPaid, PaymentStatusRefunded, PaymentStatusCancelled:
+	default:
+		errs = append(errs, fmt.Errorf("Unknown payment status %q", p.Status))
+	}
+
+	if p.Total == nil {
+		errs = append(errs, errors.New("Payment total can not be empty"))
+	} else if err := p.Total.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("Payment total: %w", err))
+	}
+
+	for _, item := range p.SummaryItems {
+		if item.Label == "" {
+			errs = append(errs, errors.New("Summary item label can not be empty"))
+		}
+
+		if item.Value == nil {
+			errs = append(errs, fmt.Errorf("Summary item %q: value can not be empty", item.Label))
+		} else if err := item.Value.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Summary item %q: %w", item.Label, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (f *Fulfillment) Validate() error {
+	var errs []error
+
+	switch {
+	case f.Shipping != nil && f.Pickup != nil:
+		return errors.New("Fulfillment can not be both shipping and pickup")
+	case f.Shipping != nil:
+		s := f.Shipping
+		if s.FulfillmentIdentifier == "" {
+			errs = append(errs, errors.New("Fulfillment identifier can not be empty"))
+		}
+
+		switch s.Status {
+		case ShippingStatusProcessing, ShippingStatusShipped, ShippingStatusOnTheWay, ShippingStatusOutForDelivery,
+			ShippingStatusDelivered, ShippingStatusIssue, ShippingStatusCancelled:
+		default:
+			errs = append(errs, fmt.Errorf("Unknown shipping status %q", s.Status))
+		}
+
+		if s.ShippingType != "" && s.ShippingType != ShippingTypeShipping && s.ShippingType != ShippingTypeDelivery {
+			errs = append(errs, fmt.Errorf("Unknown shipping type %q", s.ShippingType))
+		}
+
+		if s.TrackingURL != "" && !passkit.IsHTTPSURL(s.TrackingURL) {
+			errs = append(errs, fmt.Errorf("Tracking URL %q must be an https URL", s.TrackingURL))
+		}
+	case f.Pickup != nil:
+		p := f.Pickup
+		if p.FulfillmentIdentifier == "" {
+			errs = append(errs, errors.New("Fulfillment identifier can not be empty"))
+		}
+
+		switch p.Status {
+		case PickupStatusOpen, PickupStatusProcessing, PickupStatusReadyForPickup, PickupStatusPickedUp,
+			PickupStatusCancelled, PickupStatusIssue:
+		default:
+			errs = append(errs, fmt.Errorf("Unknown pickup status %q", p.Status))
+		}
+
+		if w := p.PickupWindow; w != nil {
+			if w.StartDate == nil || w.EndDate == nil {
+				errs = append(errs, errors.New("Pickup window needs a start and an end date"))
+			} else if !w.EndDate.After(w.StartDate.Time) {
+				errs = append(errs, errors.New("Pickup window must end after it starts"))
+			}
+		}
+
+		if p.Barcode != nil {
+			if err := p.Barcode.Validate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	default:
+		return errors.New("Fulfillment can not be empty")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the keys Wallet requires and the values it restricts, and
+// returns all problems found, joined into a single error.
+func (o *Order) Validate() error {
+	var errs []error
+
+	if o.SchemaVersion != SchemaVersion {
+		errs = append(errs, fmt.Errorf("Unsupported schema version %d", o.SchemaVersion))
+	}
+
+	if o.OrderTypeIdentifier == "" {
+		errs = append(errs, errors.New("Order type identifier can not be empty"))
+	}
+
+	if o.OrderIdentifier == "" {
+		errs = append(errs, errors.New("Order identifier can not be empty"))
+	}
+
+	if o.OrderType != OrderTypeEcommerce {
+		errs = append(errs, fmt.Errorf("Unknown order type %q", o.OrderType))
+	}
+
+	switch o.Status {
+	case OrderStatusOpen, OrderStatusCompleted, OrderStatusCancelled:
+	default:
+		errs = append(errs, fmt.Errorf("Unknown order status %q", o.Status))
+	}
+
+	if o.CreatedAt == nil {
+		errs = append(errs, errors.New("Created at can not be empty"))
+	}
+
+	if o.UpdatedAt == nil {
+		errs = append(errs, errors.New("Updated at can not be empty"))
+	} else if o.CreatedAt != nil && o.UpdatedAt.Before(o.CreatedAt.Time) {
+		errs = append(errs, errors.New("Updated at can not be before created at"))
+	}
+
+	if o.Merchant == nil {
+		errs = append(errs, errors.New("Merchant can not be empty"))
+	} else if err := o.Merchant.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if o.WebServiceURL != "" {
+		if !passkit.IsHTTPSURL(o.WebServiceURL) {
+			errs = append(errs, fmt.Errorf("Web service URL %q must be an https URL", o.WebServiceURL))
+		}
+
+		if len(o.AuthenticationToken) < MinAuthenticationTokenLength {
+			errs = append(errs, fmt.Errorf("Authentication token must be at least %d characters", MinAuthenticationTokenLength))
+		}
+	}
+
+	for i, item := range o.LineItems {
+		if item.Title == "" {
+			errs = append(errs, fmt.Errorf("Line item %d: title can not be empty", i))
+		}
+
+		if item.Price != nil {
+			if err := item.Price.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("Line item %d: %w", i, err))
+			}
+		}
+	}
+
+	if o.Payment != nil {
+		if err := o.Payment.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range o.Fulfillments {
+		f := &o.Fulfillments[i]
+		if err := f.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Fulfillment %d: %w", i, err))
+			continue
+		}
+
+		id := f.identifier()
+		if seen[id] {
+			errs = append(errs, fmt.Errorf("Fulfillment %d: identifier %q is already used", i, id))
+		}
+		seen[id] = true
+	}
+
+	return errors.Join(errs...)
+}
+
+func (f *Fulfillment) identifier() string {
+	if f.Shipping != nil {
+		return f.Shipping.FulfillmentIdentifier
+	}
+
+	return f.Pickup.FulfillmentIdentifier
+}
@@ -0,0 +1,90 @@
+package orders
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/clevtech/apple-wallet-pass/internal/webservice"
+)
+
+// ErrNotFound is returned by a Store for unknown orders and registrations.
+var ErrNotFound = webservice.ErrNotFound
+
+// Store is the persistence behind the order web service.
+type Store interface {
+	// AuthenticationToken returns the token the order was issued with.
+	AuthenticationToken(ctx context.Context, orderTypeIdentifier, orderIdentifier string) (string, error)
+
+	// Register records that a device wants updates for an order and reports
+	// whether the registration is new.
+	Register(ctx context.Context, deviceIdentifier, pushToken, orderTypeIdentifier, orderIdentifier string) (bool, error)
+
+	Unregister(ctx context.Context, deviceIdentifier, orderTypeIdentifier, orderIdentifier string) error
+
+	// RegisteredOrders returns the identifiers of the orders registered to a
+	// device that changed after modifiedSince, an opaque tag from an earlier
+	// call that is empty on the first one, and the tag to use next.
+	RegisteredOrders(ctx context.Context, deviceIdentifier, orderTypeIdentifier, modifiedSince string) ([]string, string, error)
+
+	// Order returns the signed .order archive and when it last changed.
+	Order(ctx context.Context, orderTypeIdentifier, orderIdentifier string) ([]byte, time.Time, error)
+}
+
+// Handler serves the order web service under the webServiceURL of the
+// orders, which must be stripped before it reaches the handler.
+type Handler struct {
+	Store Store
+
+	// Log receives the messages devices post about errors; it may be nil.
+	Log func(messages []string)
+
+	handler http.Handler
+}
+
+func NewHandler(store Store) *Handler {
+	h := &Handler{Store: store}
+	h.handler = webservice.NewHandler(webservice.Protocol{
+		Scheme:      "AppleOrder",
+		Items:       "orders",
+		ContentType: ContentType,
+		SinceParam:  "ordersModifiedSince",
+		IDsKey:      "orderIdentifiers",
+		TagKey:      "lastModified",
+	}, orderStore{h}, func(messages []string) {
+		if h.Log != nil {
+			h.Log(messages)
+		}
+	})
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+// orderStore serves the requests from the Store the handler holds at the time.
+type orderStore struct {
+	h *Handler
+}
+
+func (s orderStore) AuthenticationToken(ctx context.Context, typeID, id string) (string, error) {
+	return s.h.Store.AuthenticationToken(ctx, typeID, id)
+}
+
+func (s orderStore) Register(ctx context.Context, device, pushToken, typeID, id string) (bool, error) {
+	return s.h.Store.Register(ctx, device, pushToken, typeID, id)
+}
+
+func (s orderStore) Unregister(ctx context.Context, device, typeID, id string) error {
+	return s.h.Store.Unregister(ctx, device, typeID, id)
+}
+
+func (s orderStore) Updated(ctx context.Context, device, typeID, since string) ([]string, string, error) {
+	return s.h.Store.RegisteredOrders(ctx, device, typeID, since)
+}
+
+func (s orderStore) Item(ctx context.Context, typeID, id string) ([]byte, time.Time, error) {
+	return s.h.Store.Order(ctx, typeID, id)
+}
@@ -0,0 +1,68 @@
+package orders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tokenStore holds one order with the given authentication token.
+type tokenStore struct {
+	token string
+}
+
+func (s tokenStore) AuthenticationToken(ctx context.Context, orderTypeIdentifier, orderIdentifier string) (string, error) {
+	return s.token, nil
+}
+
+func (s tokenStore) Register(ctx context.Context, deviceIdentifier, pushToken, orderTypeIdentifier, orderIdentifier string) (bool, error) {
+	return true, nil
+}
+
+func (s tokenStore) Unregister(ctx context.Context, deviceIdentifier, orderTypeIdentifier, orderIdentifier string) error {
+	return nil
+}
+
+func (s tokenStore) RegisteredOrders(ctx context.Context, deviceIdentifier, orderTypeIdentifier, modifiedSince string) ([]string, string, error) {
+	return nil, "", nil
+}
+
+func (s tokenStore) Order(ctx context.Context, orderTypeIdentifier, orderIdentifier string) ([]byte, time.Time, error) {
+	return []byte("archive"), time.Now(), nil
+}
+
+func TestHandlerAuthorization(t *testing.T) {
+	requests := []struct{ method, path, body string }{
+		{"GET", "/v1/orders/order.com.example/1", ""},
+		{"POST", "/v1/devices/d1/registrations/order.com.example/1", `{"pushToken":"t"}`},
+		{"DELETE", "/v1/devices/d1/registrations/order.com.example/1", ""},
+	}
+
+	tests := []struct {
+		name, token, auth string
+		status            int
+	}{
+		{name: "no token", token: "", auth: "AppleOrder ", status: http.StatusUnauthorized},
+		{name: "empty given token", token: "secret-token-0123", auth: "AppleOrder ", status: http.StatusUnauthorized},
+		{name: "pass scheme", token: "secret-token-0123", auth: "ApplePass secret-token-0123", status: http.StatusUnauthorized},
+		{name: "matching token", token: "secret-token-0123", auth: "AppleOrder secret-token-0123"},
+	}
+
+	for _, tt := range tests {
+		h := NewHandler(tokenStore{token: tt.token})
+		for _, req := range requests {
+			r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+			r.Header.Set("Authorization", tt.auth)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if unauthorized := w.Code == http.StatusUnauthorized; unauthorized != (tt.status == http.StatusUnauthorized) {
+				t.Errorf("%s: %s %s: status %d", tt.name, req.method, req.path, w.Code)
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package passkit
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"slices"
+)
+
+// Signer produces the detached signature of a manifest.
+type Signer interface {
+	Sign(manifest []byte) ([]byte, error)
+}
+
+// Manifest returns the manifest.json of files: each file name mapped to the
+// hex digest of its contents.
+func Manifest(files map[string][]byte, newHash func() hash.Hash) ([]byte, error) {
+	return manifest(files, newHash, nil)
+}
+
+func digest(data []byte, newHash func() hash.Hash) string {
+	h := newHash()
+	h.Write(data)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// manifest is Manifest taking the digests of some files from known, so that
+// files shared by many archives are hashed once.
+func manifest(files map[string][]byte, newHash func() hash.Hash, known map[string]string) ([]byte, error) {
+	digests := make(map[string]string, len(files))
+	for name, data := range files {
+		if d, ok := known[name]; ok {
+			digests[name] = d
+			continue
+		}
+		digests[name] = digest(data, newHash)
+	}
+
+	return json.MarshalIndent(digests, "", "  ")
+}
+
+// WriteSignedArchive zips files together with their manifest.json and the
+// signature of that manifest, the layout shared by passes and orders.
+func WriteSignedArchive(w io.Writer, files map[string][]byte, newHash func() hash.Hash, signer Signer) error {
+	return writeSignedArchive(w, files, newHash, signer, nil)
+}
+
+func writeSignedArchive(w io.Writer, files map[string][]byte, newHash func() hash.Hash, signer Signer, known map[string]string) error {
+	if signer == nil {
+		return errors.New("Signer can not be empty")
+	}
+
+	for _, name := range []string{"manifest.json", "signature"} {
+		if _, ok := files[name]; ok {
+			return fmt.Errorf("Archive file %s is generated and can not be added", name)
+		}
+	}
+
+	m, err := manifest(files, newHash, known)
+	if err != nil {
+		return err
+	}
+
+	signature, err := signer.Sign(m)
+	if err != nil {
+		return fmt.Errorf("Signing manifest: %w", err)
+	}
+
+	zw := zip.NewWriter(w)
+	add := func(name string, data []byte) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+
+		return err
+	}
+
+	for _, name := range sortedKeys(files) {
+		if err := add(name, files[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := add("manifest.json", m); err != nil {
+		return err
+	}
+
+	if err := add("signature", signature); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// WriteArchive writes the pass as a signed .pkpass archive. files holds the
+// images and localizations to bundle, keyed by their path in the archive.
+func (p *Pass) WriteArchive(w io.Writer, files map[string][]byte, signer Signer) error {
+	return p.writeArchive(w, files, signer, nil)
+}
+
+func (p *Pass) writeArchive(w io.Writer, files map[string][]byte, signer Signer, known map[string]string) error {
+	data, err := p.ToJson()
+	if err != nil {
+		return err
+	}
+
+	all := maps.Clone(files)
+	if all == nil {
+		all = make(map[string][]byte)
+	}
+	all["pass.json"] = data
+
+	return writeSignedArchive(w, all, sha1.New, signer, known)
+}
+
+// ReadArchive returns the files of a .pkpass or .order archive by path.
+func ReadArchive(data []byte) (map[string][]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid archive: %w", err)
+	}
+
+	files := make(map[string][]byte, len(zr.File))
+	left := maxZipTotalSize
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		if _, ok := files[f.Name]; ok {
+			return nil, fmt.Errorf("Archive has more than one file %s", f.Name)
+		}
+
+		b, err := readZipFile(f, &left)
+		if err != nil {
+			return nil, err
+		}
+		files[f.Name] = b
+	}
+
+	return files, nil
+}
+
+// VerifyArchive checks that the manifest lists every file of the archive
+// with a matching digest and that the signature covers the manifest. The
+// certificates of the signature are returned, the signing one first; see
+// VerifySignature for roots.
+func VerifyArchive(files map[string][]byte, roots *x509.CertPool) ([]*x509.Certificate, error) {
+	manifestData, ok := files["manifest.json"]
+	if !ok {
+		return nil, errors.New("Archive has no manifest.json")
+	}
+
+	signature, ok := files["signature"]
+	if !ok {
+		return nil, errors.New("Archive has no signature")
+	}
+
+	var digests map[string]string
+	if err := json.Unmarshal(manifestData, &digests); err != nil {
+		return nil, fmt.Errorf("Invalid manifest.json: %w", err)
+	}
+
+	h, err := manifestHash(digests)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(files) {
+		if name == "manifest.json" || name == "signature" {
+			continue
+		}
+
+		want, ok := digests[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("File %s is not in the manifest", name))
+			continue
+		}
+
+		if digest(files[name], h.New) != want {
+			errs = append(errs, fmt.Errorf("File %s does not match its manifest digest", name))
+		}
+	}
+
+	for _, name := range sortedKeys(digests) {
+		if _, ok := files[name]; !ok {
+			errs = append(errs, fmt.Errorf("File %s of the manifest is missing", name))
+		}
+	}
+
+	certs, err := VerifySignature(manifestData, signature, roots)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return certs, errors.Join(errs...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,174 @@
+package passkit
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// AttributedValueBuilder builds an attributedValue out of plain text and
+// links, escaping both so the result only ever contains <a href> anchors.
+// Links Wallet would not open are written as plain text and reported by Err.
+type AttributedValueBuilder struct {
+	b    strings.Builder
+	errs []error
+}
+
+func NewAttributedValue() *AttributedValueBuilder {
+	return &AttributedValueBuilder{}
+}
+
+func (a *AttributedValueBuilder) Text(text string) *AttributedValueBuilder {
+	a.b.WriteString(html.EscapeString(text))
+
+	return a
+}
+
+func (a *AttributedValueBuilder) Link(href, text string) *AttributedValueBuilder {
+	if err := validateLinkHref(href); err != nil {
+		a.errs = append(a.errs, err)
+		return a.Text(text)
+	}
+
+	fmt.Fprintf(&a.b, `<a href="%s">%s</a>`, html.EscapeString(href), html.EscapeString(text))
+
+	return a
+}
+
+func (a *AttributedValueBuilder) String() string {
+	return a.b.String()
+}
+
+// Err returns the errors of the links that were left out.
+func (a *AttributedValueBuilder) Err() error {
+	return errors.Join(a.errs...)
+}
+
+// linkSchemes are the URL schemes Wallet opens from attributed values.
+var linkSchemes = map[string]bool{"http": true, "https": true, "tel": true, "mailto": true}
+
+func validateLinkHref(href string) error {
+	if href == "" {
+		return errors.New("Link in attributed value must have an href")
+	}
+
+	u, err := url.Parse(href)
+	if err != nil || !linkSchemes[strings.ToLower(u.Scheme)] || (strings.HasPrefix(strings.ToLower(u.Scheme), "http") && u.Host == "") {
+		return fmt.Errorf("Link %q in attributed value must be an http, https, tel or mailto URL", href)
+	}
+
+	return nil
+}
+
+// ValidateAttributedValue checks that value only uses the HTML Wallet
+// supports in attributedValue: <a href> anchors that are not nested.
+func ValidateAttributedValue(value string) error {
+	d := xml.NewDecoder(strings.NewReader(value))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Invalid attributed value: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if !strings.EqualFold(t.Name.Local, "a") {
+				return fmt.Errorf("Unsupported tag <%s> in attributed value, only <a href> is allowed", t.Name.Local)
+			}
+
+			if depth > 0 {
+				return errors.New("Links can not be nested in attributed value")
+			}
+
+			href := ""
+			for _, attr := range t.Attr {
+				if !strings.EqualFold(attr.Name.Local, "href") {
+					return fmt.Errorf("Unsupported attribute %q on <a> in attributed value", attr.Name.Local)
+				}
+				href = attr.Value
+			}
+
+			if err := validateLinkHref(href); err != nil {
+				return err
+			}
+
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.ProcInst, xml.Directive:
+			return errors.New("Attributed value can only contain text and <a href> links")
+		}
+	}
+
+	return nil
+}
+
+func isValidDataDetectorType(t DataDetectorType) bool {
+	switch t {
+	case DataDetectorTypePhoneNumber, DataDetectorTypeLink, DataDetectorTypeAddress, DataDetectorTypeCalendarEvent:
+		return true
+	}
+
+	return false
+}
+
+// Validate checks every field of the pass style. Attributed values and data
+// detectors are only honored on the back of the pass, so they are rejected on
+// the front field groups.
+func (f *PassFields) Validate() error {
+	var errs []error
+
+	groups := []struct {
+		name   string
+		fields []PassFieldContent
+		back   bool
+	}{
+		{"Header fields", f.HeaderFields, false},
+		{"Primary fields", f.PrimaryFields, false},
+		{"Secondary fields", f.SecondaryFields, false},
+		{"Auxiliary fields", f.AuxiliaryFields, false},
+		{"Back fields", f.BackFields, true},
+	}
+
+	for _, g := range groups {
+		for i := range g.fields {
+			field := &g.fields[i]
+
+			if err := field.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", g.name, err))
+			}
+
+			if field.AttributedValue != "" {
+				if !g.back {
+					errs = append(errs, fmt.Errorf("%s: field %q: attributed value is only supported on back fields", g.name, field.Key))
+				} else if err := ValidateAttributedValue(field.AttributedValue); err != nil {
+					errs = append(errs, fmt.Errorf("%s: field %q: %w", g.name, field.Key, err))
+				}
+			}
+
+			if len(field.DataDetectorTypes) > 0 && !g.back {
+				errs = append(errs, fmt.Errorf("%s: field %q: data detectors are only supported on back fields", g.name, field.Key))
+			}
+
+			for _, t := range field.DataDetectorTypes {
+				if !isValidDataDetectorType(t) {
+					errs = append(errs, fmt.Errorf("%s: field %q: unknown data detector type %q", g.name, field.Key, t))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package passkit
+
+import "testing"
+
+func TestValidateAttributedValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: `Call <a href="tel:+15555550123">us</a> or <a href="mailto:help@example.com">mail</a>`},
+		{value: `See <a href="https://example.com/terms?a=1&amp;b=2">terms</a>`},
+		{value: `<A HREF="HTTP://example.com">x</A>`},
+		{value: `<a href="javascript:alert(1)">x</a>`, want: `Link "javascript:alert(1)" in attributed value must be an http, https, tel or mailto URL`},
+		{value: `<a href="data:text/html,hi">x</a>`, want: `Link "data:text/html,hi" in attributed value must be an http, https, tel or mailto URL`},
+		{value: `<a href="/relative">x</a>`, want: `Link "/relative" in attributed value must be an http, https, tel or mailto URL`},
+		{value: `<a href="https:">x</a>`, want: `Link "https:" in attributed value must be an http, https, tel or mailto URL`},
+		{value: `<a>x</a>`, want: "Link in attributed value must have an href"},
+		{value: `<b>x</b>`, want: "Unsupported tag <b> in attributed value, only <a href> is allowed"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateAttributedValue(tt.value)
+		if tt.want == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.value, err)
+		}
+		if tt.want != "" && (err == nil || err.Error() != tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestAttributedValueBuilder(t *testing.T) {
+	a := NewAttributedValue().Text("Read <this> ").Link("https://example.com/?a=1&b=2", "terms").Text(" or ").Link("javascript:alert(1)", "click")
+
+	want := `Read &lt;this&gt; <a href="https://example.com/?a=1&amp;b=2">terms</a> or click`
+	if a.String() != want {
+		t.Errorf("built %s, want %s", a, want)
+	}
+	if err := ValidateAttributedValue(a.String()); err != nil {
+		t.Errorf("built value does not validate: %v", err)
+	}
+
+	if err := a.Err(); err == nil || err.Error() != `Link "javascript:alert(1)" in attributed value must be an http, https, tel or mailto URL` {
+		t.Errorf("Err() = %v", err)
+	}
+}
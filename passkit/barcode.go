@@ -0,0 +1,150 @@
+package passkit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const DefaultMessageEncoding = "iso-8859-1"
+
+var ErrUnsupportedEncoding = errors.New("Unsupported message encoding")
+
+// Largest payloads, in bytes, that fit each symbology at the error correction
+// level Wallet renders with: QR level M, Aztec 23% and PDF417 level 2.
+const (
+	MaxAztecBytes   = 1914
+	MaxCode128Chars = 80
+	MaxPDF417Bytes  = 1101
+	MaxQRBytes      = 2331
+)
+
+// ianaCharsets holds the IANA charset names and aliases accepted for
+// messageEncoding, lower cased.
+var ianaCharsets = strings.Fields(`
+	us-ascii ascii iso646-us ansi_x3.4-1968 utf-8 utf-16
+	utf-16be utf-16le utf-32 utf-32be utf-32le iso-8859-1
+	iso_8859-1 latin1 l1 iso-8859-2 iso-8859-3 iso-8859-4
+	iso-8859-5 iso-8859-6 iso-8859-7 iso-8859-8 iso-8859-9 iso-8859-10
+	iso-8859-13 iso-8859-14 iso-8859-15 iso-8859-16 windows-1250 windows-1251
+	windows-1252 windows-1253 windows-1254 windows-1255 windows-1256 windows-1257
+	windows-1258 shift_jis euc-jp iso-2022-jp euc-kr iso-2022-kr
+	gb2312 gbk gb18030 big5 koi8-r koi8-u
+`)
+
+func isValidMessageEncoding(encoding string) bool {
+	return slices.Contains(ianaCharsets, strings.ToLower(encoding))
+}
+
+// EncodeBarcodeMessage returns message encoded in the given IANA charset.
+// Only the Unicode and Latin-1 family of charsets can be encoded.
+func EncodeBarcodeMessage(message, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case "utf-8":
+		return []byte(message), nil
+	case "us-ascii", "ascii", "iso646-us", "ansi_x3.4-1968":
+		return encodeSingleByte(message, 0x7f, encoding)
+	case "iso-8859-1", "iso_8859-1", "latin1", "l1":
+		return encodeSingleByte(message, 0xff, encoding)
+	case "utf-16", "utf-16be":
+		out := []byte{}
+		if strings.EqualFold(encoding, "utf-16") {
+			out = append(out, 0xfe, 0xff)
+		}
+		for _, u := range utf16.Encode([]rune(message)) {
+			out = append(out, byte(u>>8), byte(u))
+		}
+		return out, nil
+	case "utf-16le":
+		out := []byte{}
+		for _, u := range utf16.Encode([]rune(message)) {
+			out = append(out, byte(u), byte(u>>8))
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("%w %q", ErrUnsupportedEncoding, encoding)
+}
+
+func encodeSingleByte(message string, max rune, encoding string) ([]byte, error) {
+	out := make([]byte, 0, len(message))
+	for _, r := range message {
+		if r > max {
+			return nil, fmt.Errorf("Character %q can not be encoded in %s", r, encoding)
+		}
+		out = append(out, byte(r))
+	}
+
+	return out, nil
+}
+
+// Validate checks the barcode message against its encoding and the capacity
+// of its format.
+func (b *Barcodes) Validate() error {
+	if b.Message == "" {
+		return errors.New("Barcode message can not be empty")
+	}
+
+	if b.MessageEncoding == "" {
+		return errors.New("Barcode message encoding can not be empty")
+	}
+
+	if !isValidMessageEncoding(b.MessageEncoding) {
+		return fmt.Errorf("Barcode message encoding %q is not an IANA charset name", b.MessageEncoding)
+	}
+
+	// Charsets we can not encode are measured as UTF-8, which is an upper
+	// bound for most of them.
+	size := len(b.Message)
+	if data, err := EncodeBarcodeMessage(b.Message, b.MessageEncoding); err == nil {
+		size = len(data)
+	} else if !errors.Is(err, ErrUnsupportedEncoding) {
+		return fmt.Errorf("Barcode message: %w", err)
+	}
+
+	switch b.Format {
+	case BarcodeFormatCode128:
+		for _, r := range b.Message {
+			if r >= utf8.RuneSelf {
+				return fmt.Errorf("Code128 barcode message can only contain ASCII characters, got %q", r)
+			}
+		}
+		if len(b.Message) > MaxCode128Chars {
+			return fmt.Errorf("Code128 barcode message is %d characters, limit is %d", len(b.Message), MaxCode128Chars)
+		}
+	case BarcodeFormatQR:
+		if size > MaxQRBytes {
+			return fmt.Errorf("QR barcode message is %d bytes, limit is %d", size, MaxQRBytes)
+		}
+	case BarcodeFormatAztec:
+		if size > MaxAztecBytes {
+			return fmt.Errorf("Aztec barcode message is %d bytes, limit is %d", size, MaxAztecBytes)
+		}
+	case BarcodeFormatPDF417:
+		if size > MaxPDF417Bytes {
+			return fmt.Errorf("PDF417 barcode message is %d bytes, limit is %d", size, MaxPDF417Bytes)
+		}
+	case "":
+		return errors.New("Barcode format can not be empty")
+	default:
+		return fmt.Errorf("Unknown barcode format %q", b.Format)
+	}
+
+	return nil
+}
+
+// legacyBarcode picks the entry for the deprecated barcode key read by iOS 8
+// and earlier, which can not display Code128.
+func (p *Pass) legacyBarcode() *Barcodes {
+	for i := range p.Barcodes {
+		if p.Barcodes[i].Format != BarcodeFormatCode128 {
+			b := p.Barcodes[i]
+			return &b
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package passkit
+
+import "testing"
+
+func TestSetBarcodesReplacesLegacyBarcode(t *testing.T) {
+	p, err := DecodePass(decodeTestJSON(`"eventTicket": {}, "barcode": {"format": "PKBarcodeFormatQR", "message": "OLD", "messageEncoding": "iso-8859-1"}`), DecodeLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.SetBarcodes([]Barcodes{
+		{Format: BarcodeFormatCode128, Message: "NEW", MessageEncoding: DefaultMessageEncoding},
+		{Format: BarcodeFormatQR, Message: "NEW", MessageEncoding: DefaultMessageEncoding},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := p.ToJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodePass(data, DecodeLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Code 128 is not readable before iOS 9, so the legacy key takes the QR code.
+	if b := decoded.Barcode; b == nil || b.Format != BarcodeFormatQR || b.Message != "NEW" {
+		t.Errorf("barcode = %+v, want the new QR code", b)
+	}
+}
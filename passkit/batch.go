@@ -0,0 +1,335 @@
+package passkit
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Record is the data of one pass in a batch. ID names the pass in the output
+// and the error report; the serial number is used when it is empty.
+type Record struct {
+	ID   string
+	Data any
+}
+
+// RecordError reports a record that did not produce a pass.
+type RecordError struct {
+	ID  string
+	Err error
+}
+
+func (e RecordError) Error() string {
+	return fmt.Sprintf("Record %s: %v", e.ID, e.Err)
+}
+
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// BatchOutput receives the signed archives of a batch. Write is never called
+// concurrently.
+type BatchOutput interface {
+	Write(id string, archive []byte) error
+}
+
+type BatchOptions struct {
+	// Workers is the number of passes rendered and signed at once. It
+	// defaults to the number of CPUs.
+	Workers int
+
+	// Progress, if set, is called after every record with the number of
+	// records done and how many of them failed.
+	Progress func(done, failed int)
+}
+
+type dirOutput string
+
+func (d dirOutput) Write(id string, archive []byte) error {
+	return os.WriteFile(filepath.Join(string(d), id+".pkpass"), archive, 0o644)
+}
+
+// DirOutput writes every pass to dir as <id>.pkpass.
+func DirOutput(dir string) BatchOutput {
+	return dirOutput(dir)
+}
+
+// ZipOutput streams every pass into a zip file as <id>.pkpass.
+type ZipOutput struct {
+	zw *zip.Writer
+}
+
+func NewZipOutput(w io.Writer) *ZipOutput {
+	return &ZipOutput{zw: zip.NewWriter(w)}
+}
+
+func (z *ZipOutput) Write(id string, archive []byte) error {
+	f, err := z.zw.CreateHeader(&zip.FileHeader{Name: id + ".pkpass", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(archive)
+
+	return err
+}
+
+// Close finishes the zip file; it does not close the underlying writer.
+func (z *ZipOutput) Close() error {
+	return z.zw.Close()
+}
+
+// RecordSource starts sending records on the returned channel and closes it
+// when there are no more or ctx is done.
+type RecordSource func(ctx context.Context) <-chan Record
+
+// CSVRecords reads the rows of a CSV file with a header line as records whose
+// Data maps column names to values, taking the ID from idColumn. The returned
+// function reports the read error, if any, once the source is exhausted.
+func CSVRecords(r io.Reader, idColumn string) (RecordSource, func() error) {
+	var err error
+
+	source := func(ctx context.Context) <-chan Record {
+		records := make(chan Record)
+
+		go func() {
+			defer close(records)
+
+			cr := csv.NewReader(r)
+			header, e := cr.Read()
+			if e != nil {
+				err = e
+				return
+			}
+
+			for {
+				row, e := cr.Read()
+				if e == io.EOF {
+					return
+				}
+				if e != nil {
+					err = e
+					return
+				}
+
+				data := make(map[string]string, len(header))
+				for i, col := range header {
+					if i < len(row) {
+						data[col] = row[i]
+					}
+				}
+
+				select {
+				case records <- Record{ID: data[idColumn], Data: data}:
+				case <-ctx.Done():
+					err = ctx.Err()
+					return
+				}
+			}
+		}()
+
+		return records
+	}
+
+	return source, func() error { return err }
+}
+
+func validRecordID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
+
+var errDuplicateRecordID = errors.New("Duplicate record ID")
+
+type batchResult struct {
+	id string
+
+	// claimed is set when the ID was given by the record and so claimed in
+	// source order before rendering.
+	claimed bool
+	archive []byte
+	err     error
+}
+
+// recordIDs are the IDs claimed so far in a batch.
+type recordIDs struct {
+	mu  sync.Mutex
+	ids map[string]bool
+}
+
+// claim reports whether id was still free, taking it if so.
+func (r *recordIDs) claim(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ids[id] {
+		return false
+	}
+	r.ids[id] = true
+
+	return true
+}
+
+// Generate renders, validates and signs a pass for every record of source,
+// writing them to out as they complete. Records that fail, including those
+// whose ID another record already used, are reported in the returned slice
+// and do not stop the batch. Record IDs are claimed in source order, so the
+// first record with an ID wins; IDs taken from serial numbers are only known
+// after rendering, and among those the first pass done wins. The error is
+// only set if the context is cancelled or out fails, in which case nothing
+// more is written and source is stopped.
+func (t *Template) Generate(ctx context.Context, source RecordSource, signer Signer, out BatchOutput, opts BatchOptions) ([]RecordError, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// The images are the same in every pass, so hash them once.
+	known := make(map[string]string, len(t.assets))
+	for name, data := range t.assets {
+		known[name] = digest(data, sha1.New)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	records := source(ctx)
+	defer func() {
+		// Wait for the source to see the cancellation and close.
+		cancel()
+		for range records {
+		}
+	}()
+
+	ids := &recordIDs{ids: make(map[string]bool)}
+	jobs := make(chan Record)
+	results := make(chan batchResult)
+	var wg sync.WaitGroup
+
+	// Claim the given IDs in source order before the records are rendered
+	// concurrently.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case rec, ok := <-records:
+				if !ok {
+					return
+				}
+
+				if rec.ID != "" && !ids.claim(rec.ID) {
+					select {
+					case results <- batchResult{id: rec.ID, err: errDuplicateRecordID}:
+					case <-ctx.Done():
+						return
+					}
+					continue
+				}
+
+				select {
+				case jobs <- rec:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rec := range jobs {
+				res := t.generate(rec, signer, known)
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var (
+		failures []RecordError
+		outErr   error
+		done     int
+	)
+	for res := range results {
+		if outErr != nil {
+			continue
+		}
+
+		done++
+		switch {
+		case res.err != nil:
+			failures = append(failures, RecordError{ID: res.id, Err: res.err})
+		case !res.claimed && !ids.claim(res.id):
+			failures = append(failures, RecordError{ID: res.id, Err: errDuplicateRecordID})
+		default:
+			if err := out.Write(res.id, res.archive); err != nil {
+				outErr = fmt.Errorf("Writing %s: %w", res.id, err)
+				cancel()
+			}
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(done, len(failures))
+		}
+	}
+
+	if outErr != nil {
+		return failures, outErr
+	}
+
+	return failures, context.Cause(ctx)
+}
+
+func (t *Template) generate(rec Record, signer Signer, known map[string]string) batchResult {
+	res := batchResult{id: rec.ID, claimed: rec.ID != ""}
+
+	p, files, err := t.Render(rec.Data)
+	if err != nil {
+		res.err = err
+		return res
+	}
+
+	if res.id == "" {
+		res.id = p.SerialNumber
+	}
+	if !validRecordID(res.id) {
+		res.err = fmt.Errorf("Invalid record ID %q", res.id)
+		return res
+	}
+
+	if err := p.Validate(); err != nil {
+		res.err = err
+		return res
+	}
+
+	var buf bytes.Buffer
+	if err := p.writeArchive(&buf, files, signer, known); err != nil {
+		res.err = err
+		return res
+	}
+	res.archive = buf.Bytes()
+
+	return res
+}
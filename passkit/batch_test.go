@@ -0,0 +1,219 @@
+package passkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(manifest []byte) ([]byte, error) {
+	return []byte("signature"), nil
+}
+
+type memOutput struct {
+	archives map[string][]byte
+	err      error
+}
+
+func (m *memOutput) Write(id string, archive []byte) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.archives[id] = archive
+
+	return nil
+}
+
+func (m *memOutput) ids() []string {
+	var ids []string
+	for id := range m.archives {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func testTemplate(t *testing.T) *Template {
+	t.Helper()
+
+	tmpl, err := LoadTemplateFS(fstest.MapFS{
+		"pass.json": {Data: []byte(`{
+			"formatVersion": 1,
+			"passTypeIdentifier": "pass.com.example",
+			"serialNumber": "{{.serial}}",
+			"teamIdentifier": "ABCDE12345",
+			"organizationName": "Example",
+			"description": "Coupon",
+			"coupon": {}
+		}`)},
+		"icon.png": {Data: []byte("icon")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tmpl
+}
+
+func sliceRecords(records ...Record) RecordSource {
+	return func(ctx context.Context) <-chan Record {
+		ch := make(chan Record)
+		go func() {
+			defer close(ch)
+			for _, rec := range records {
+				select {
+				case ch <- rec:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		return ch
+	}
+}
+
+func serialRecords(serials ...string) RecordSource {
+	records := make([]Record, len(serials))
+	for i, serial := range serials {
+		records[i] = Record{Data: map[string]string{"serial": serial}}
+	}
+
+	return sliceRecords(records...)
+}
+
+func archiveSerial(t *testing.T, archive []byte) string {
+	t.Helper()
+
+	files, err := ReadArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := DecodePass(files["pass.json"], DecodeLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p.SerialNumber
+}
+
+func TestGenerateDuplicateIDs(t *testing.T) {
+	out := &memOutput{archives: make(map[string][]byte)}
+
+	failures, err := testTemplate(t).Generate(context.Background(), serialRecords("a", "b", "a"), fakeSigner{}, out, BatchOptions{Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := out.ids(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("written %v, want [a b]", got)
+	}
+	if len(failures) != 1 || failures[0].Error() != "Record a: Duplicate record ID" {
+		t.Errorf("failures = %v", failures)
+	}
+}
+
+func TestGenerateDuplicateIDsInSourceOrder(t *testing.T) {
+	var records []Record
+	for i := 0; i < 20; i++ {
+		records = append(records, Record{ID: fmt.Sprint("id", i%4), Data: map[string]string{"serial": fmt.Sprint(i)}})
+	}
+
+	tmpl := testTemplate(t)
+	for run := 0; run < 10; run++ {
+		out := &memOutput{archives: make(map[string][]byte)}
+
+		failures, err := tmpl.Generate(context.Background(), sliceRecords(records...), fakeSigner{}, out, BatchOptions{Workers: 8})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(failures) != 16 {
+			t.Fatalf("%d failures, want 16", len(failures))
+		}
+		for i := 0; i < 4; i++ {
+			id := fmt.Sprint("id", i)
+			if got := archiveSerial(t, out.archives[id]); got != fmt.Sprint(i) {
+				t.Errorf("run %d: %s has serial number %s, want the first record's %d", run, id, got, i)
+			}
+		}
+	}
+}
+
+func TestGenerateOutputError(t *testing.T) {
+	stopped := make(chan struct{})
+	endless := func(ctx context.Context) <-chan Record {
+		records := make(chan Record)
+		go func() {
+			defer close(stopped)
+			defer close(records)
+			for i := 0; ; i++ {
+				select {
+				case records <- Record{Data: map[string]string{"serial": fmt.Sprint(i)}}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		return records
+	}
+
+	writeErr := errors.New("disk full")
+	var progress int
+	opts := BatchOptions{Workers: 4, Progress: func(done, failed int) { progress = done }}
+
+	_, err := testTemplate(t).Generate(context.Background(), endless, fakeSigner{}, &memOutput{err: writeErr}, opts)
+	if !errors.Is(err, writeErr) {
+		t.Fatalf("error = %v, want %v", err, writeErr)
+	}
+	if progress != 1 {
+		t.Errorf("%d records handled after the first failed write, want 1", progress)
+	}
+
+	select {
+	case <-stopped:
+	default:
+		t.Error("record source still running after Generate returned")
+	}
+}
+
+func TestGenerateCSV(t *testing.T) {
+	csv := "serial,name\n1,Ann\n2,Bob\n../3,Eve\n"
+	source, readErr := CSVRecords(strings.NewReader(csv), "serial")
+
+	dir := t.TempDir()
+	failures, err := testTemplate(t).Generate(context.Background(), source, fakeSigner{}, DirOutput(dir), BatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := readErr(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failures) != 1 || failures[0].ID != "../3" {
+		t.Errorf("failures = %v, want one for ../3", failures)
+	}
+
+	for _, id := range []string{"1", "2"} {
+		data, err := os.ReadFile(filepath.Join(dir, id+".pkpass"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := archiveSerial(t, data); got != id {
+			t.Errorf("%s.pkpass has serial number %q", id, got)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package passkit
+
+import (
+	"errors"
+	"time"
+)
+
+// Builder constructs a Pass without checking an error at every step. Errors
+// of its setters are collected and reported, together with the result of
+// Pass.Validate, by Build.
+type Builder struct {
+	pass *Pass
+	errs []error
+}
+
+func NewBuilder() *Builder {
+	return &Builder{pass: NewPass()}
+}
+
+func (b *Builder) check(err error) *Builder {
+	if err != nil {
+		b.errs = append(b.errs, err)
+	}
+
+	return b
+}
+
+func (b *Builder) Org(name string) *Builder {
+	return b.check(b.pass.SetOrganizationName(name))
+}
+
+func (b *Builder) Serial(number string) *Builder {
+	return b.check(b.pass.SetSerialNumber(number))
+}
+
+func (b *Builder) PassType(identifier string) *Builder {
+	return b.check(b.pass.SetPassTypeIdentifier(identifier))
+}
+
+func (b *Builder) Team(identifier string) *Builder {
+	return b.check(b.pass.SetTeamIdentifier(identifier))
+}
+
+func (b *Builder) Description(description string) *Builder {
+	return b.check(b.pass.SetDescription(description))
+}
+
+func (b *Builder) LogoText(text string) *Builder {
+	return b.check(b.pass.SetLogoText(text))
+}
+
+// Colors sets the background, foreground and label colors. Empty values
+// leave the color unset.
+func (b *Builder) Colors(background, foreground, label string) *Builder {
+	if background != "" {
+		b.check(b.pass.SetBackgroundColor(background))
+	}
+
+	if foreground != "" {
+		b.check(b.pass.SetForegroundColor(foreground))
+	}
+
+	if label != "" {
+		b.check(b.pass.SetLabelColor(label))
+	}
+
+	return b
+}
+
+// Barcode adds a barcode with the default message encoding.
+func (b *Builder) Barcode(format BarcodeFormat, message, altText string) *Builder {
+	return b.check(b.pass.SetBarcodes(append(b.pass.Barcodes, Barcodes{
+		AltText:         altText,
+		Format:          format,
+		Message:         message,
+		MessageEncoding: DefaultMessageEncoding,
+	})))
+}
+
+func (b *Builder) Location(latitude, longitude float64, relevantText string) *Builder {
+	return b.check(b.pass.SetLocations(append(b.pass.Locations, Locations{
+		Latitude:     latitude,
+		Longitude:    longitude,
+		RelevantText: relevantText,
+	})))
+}
+
+func (b *Builder) RelevantDate(date time.Time) *Builder {
+	return b.check(b.pass.SetRelevantDates(append(b.pass.RelevantDates, NewRelevantDate(date))))
+}
+
+func (b *Builder) RelevantInterval(start, end time.Time) *Builder {
+	return b.check(b.pass.SetRelevantDates(append(b.pass.RelevantDates, NewRelevantInterval(start, end))))
+}
+
+func (b *Builder) Expiration(date time.Time) *Builder {
+	return b.check(b.pass.SetExpirationDate(&date))
+}
+
+func (b *Builder) WebService(url, authenticationToken string) *Builder {
+	b.check(b.pass.SetWebServiceURL(url))
+
+	return b.check(b.pass.SetAuthenticationToken(authenticationToken))
+}
+
+func (b *Builder) Semantics(semantics *SemanticTags) *Builder {
+	return b.check(b.pass.SetSemantics(semantics))
+}
+
+func (b *Builder) UserInfo(userInfo any) *Builder {
+	return b.check(b.pass.SetUserInfo(userInfo))
+}
+
+func (b *Builder) SharingProhibited() *Builder {
+	return b.check(b.pass.SetSharingProhibited(true))
+}
+
+// With runs fn on the pass under construction, for keys the builder has no
+// method for.
+func (b *Builder) With(fn func(p *Pass) error) *Builder {
+	return b.check(fn(b.pass))
+}
+
+func (b *Builder) BoardingPass(transitType TransitType, fn func(f *PassFields)) *Builder {
+	style := NewBoardingPass(transitType)
+	fn(style.PassFields)
+
+	return b.check(b.pass.SetBoardingPass(style))
+}
+
+func (b *Builder) Coupon(fn func(f *PassFields)) *Builder {
+	style := NewCoupon()
+	fn(style.PassFields)
+
+	return b.check(b.pass.SetCoupon(style))
+}
+
+func (b *Builder) EventTicket(fn func(f *PassFields)) *Builder {
+	style := NewEventTicket()
+	fn(style.PassFields)
+
+	return b.check(b.pass.SetEventTicket(style))
+}
+
+func (b *Builder) Generic(fn func(f *PassFields)) *Builder {
+	style := NewGeneric()
+	fn(style.PassFields)
+
+	return b.check(b.pass.SetGeneric(style))
+}
+
+func (b *Builder) StoreCard(fn func(f *PassFields)) *Builder {
+	style := NewStoreCard()
+	fn(style.PassFields)
+
+	return b.check(b.pass.SetStoreCard(style))
+}
+
+// Build returns the pass, or every error collected while building it and
+// found by Pass.Validate.
+func (b *Builder) Build() (*Pass, error) {
+	if err := errors.Join(append(b.errs, b.pass.Validate())...); err != nil {
+		return nil, err
+	}
+
+	return b.pass, nil
+}
+
+func (f *PassFields) AddHeaderField(key, label string, value *FieldValue) *PassFields {
+	f.HeaderFields = append(f.HeaderFields, PassFieldContent{Key: key, Label: label, Value: value})
+
+	return f
+}
+
+func (f *PassFields) AddPrimaryField(key, label string, value *FieldValue) *PassFields {
+	f.PrimaryFields = append(f.PrimaryFields, PassFieldContent{Key: key, Label: label, Value: value})
+
+	return f
+}
+
+func (f *PassFields) AddSecondaryField(key, label string, value *FieldValue) *PassFields {
+	f.SecondaryFields = append(f.SecondaryFields, PassFieldContent{Key: key, Label: label, Value: value})
+
+	return f
+}
+
+func (f *PassFields) AddAuxiliaryField(key, label string, value *FieldValue) *PassFields {
+	f.AuxiliaryFields = append(f.AuxiliaryFields, PassFieldContent{Key: key, Label: label, Value: value})
+
+	return f
+}
+
+func (f *PassFields) AddBackField(key, label string, value *FieldValue) *PassFields {
+	f.BackFields = append(f.BackFields, PassFieldContent{Key: key, Label: label, Value: value})
+
+	return f
+}
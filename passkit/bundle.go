@@ -0,0 +1,198 @@
+package passkit
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	PassContentType   = "application/vnd.apple.pkpass"
+	BundleContentType = "application/vnd.apple.pkpasses"
+
+	// MaxBundlePasses and MaxBundleSize are the limits Wallet puts on a
+	// .pkpasses bundle.
+	MaxBundlePasses = 10
+	MaxBundleSize   = 150 << 20
+)
+
+var requiredPassFiles = []string{"pass.json", "manifest.json", "signature"}
+
+// maxZipEntrySize and maxZipTotalSize bound how far the files of an archive
+// or bundle may expand, so a small zip can not exhaust memory.
+var (
+	maxZipEntrySize int64 = 64 << 20
+	maxZipTotalSize int64 = MaxBundleSize
+)
+
+// readZipFile reads f, taking its size from the bytes left of the total.
+func readZipFile(f *zip.File, left *int64) ([]byte, error) {
+	limit := min(maxZipEntrySize, *left)
+	tooLarge := func() error {
+		if limit < maxZipEntrySize {
+			return fmt.Errorf("Archive files take more than %d bytes", maxZipTotalSize)
+		}
+
+		return fmt.Errorf("Archive file %s takes more than %d bytes", f.Name, maxZipEntrySize)
+	}
+
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, tooLarge()
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(b)) > limit {
+		return nil, tooLarge()
+	}
+	*left -= int64(len(b))
+
+	return b, nil
+}
+
+// ReadPassArchive decodes the pass.json of a .pkpass archive. It checks that
+// the archive carries a manifest and signature but does not verify them.
+func ReadPassArchive(data []byte) (*Pass, error) {
+	files, err := ReadArchive(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range requiredPassFiles {
+		if _, ok := files[name]; !ok {
+			return nil, fmt.Errorf("Pass archive has no %s", name)
+		}
+	}
+
+	var p Pass
+	if err := json.Unmarshal(files["pass.json"], &p); err != nil {
+		return nil, fmt.Errorf("Invalid pass.json: %w", err)
+	}
+
+	return &p, nil
+}
+
+// WriteBundle zips signed .pkpass archives into a .pkpasses bundle.
+func WriteBundle(w io.Writer, passes [][]byte) error {
+	if len(passes) == 0 {
+		return errors.New("Bundle passes can not be empty")
+	}
+
+	if len(passes) > MaxBundlePasses {
+		return fmt.Errorf("Bundle has %d passes, at most %d are allowed", len(passes), MaxBundlePasses)
+	}
+
+	size := 0
+	for i, data := range passes {
+		if _, err := ReadPassArchive(data); err != nil {
+			return fmt.Errorf("Pass %d: %w", i, err)
+		}
+		size += len(data)
+	}
+
+	if size > MaxBundleSize {
+		return fmt.Errorf("Bundle passes take %d bytes, at most %d are allowed", size, MaxBundleSize)
+	}
+
+	zw := zip.NewWriter(w)
+	for i, data := range passes {
+		// The archives are compressed already.
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: fmt.Sprintf("pass%d.pkpass", i+1), Method: zip.Store})
+		if err != nil {
+			return err
+		}
+
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// ReadBundle returns the .pkpass archives of a .pkpasses bundle in order.
+func ReadBundle(data []byte) ([][]byte, error) {
+	if len(data) > MaxBundleSize {
+		return nil, fmt.Errorf("Bundle takes %d bytes, at most %d are allowed", len(data), MaxBundleSize)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid bundle: %w", err)
+	}
+
+	var passes [][]byte
+	names := make(map[string]bool)
+	left := maxZipTotalSize
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		if names[f.Name] {
+			return nil, fmt.Errorf("Bundle has more than one file %s", f.Name)
+		}
+		names[f.Name] = true
+
+		if len(passes) == MaxBundlePasses {
+			return nil, fmt.Errorf("Bundle has more than %d passes", MaxBundlePasses)
+		}
+
+		b, err := readZipFile(f, &left)
+		if err != nil {
+			return nil, err
+		}
+		passes = append(passes, b)
+	}
+
+	if len(passes) == 0 {
+		return nil, errors.New("Bundle has no passes")
+	}
+
+	return passes, nil
+}
+
+// ReadBundlePasses decodes every pass of a .pkpasses bundle.
+func ReadBundlePasses(data []byte) ([]*Pass, error) {
+	archives, err := ReadBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	passes := make([]*Pass, len(archives))
+	for i, a := range archives {
+		if passes[i], err = ReadPassArchive(a); err != nil {
+			return nil, fmt.Errorf("Pass %d: %w", i, err)
+		}
+	}
+
+	return passes, nil
+}
+
+// ServeBundle writes the passes as a .pkpasses download named filename.
+func ServeBundle(w http.ResponseWriter, filename string, passes [][]byte) error {
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, passes); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", BundleContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", fmt.Sprint(buf.Len()))
+	_, err := buf.WriteTo(w)
+
+	return err
+}
@@ -0,0 +1,93 @@
+package passkit
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func zipFiles(t *testing.T, files ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		f, err := zw.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestReadArchiveLimits(t *testing.T) {
+	defer func(entry, total int64) {
+		maxZipEntrySize, maxZipTotalSize = entry, total
+	}(maxZipEntrySize, maxZipTotalSize)
+	maxZipEntrySize, maxZipTotalSize = 1000, 2500
+
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{
+			name:  "within limits",
+			files: []string{"a", strings.Repeat("a", 1000), "b", strings.Repeat("b", 1000), "c", strings.Repeat("c", 500)},
+		},
+		{
+			name:  "file too large",
+			files: []string{"pass.json", "{}", "strip.png", strings.Repeat("x", 1001)},
+			want:  "Archive file strip.png takes more than 1000 bytes",
+		},
+		{
+			name:  "total too large",
+			files: []string{"a", strings.Repeat("a", 1000), "b", strings.Repeat("b", 1000), "c", strings.Repeat("c", 501)},
+			want:  "Archive files take more than 2500 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := zipFiles(t, tt.files...)
+
+			_, err := ReadArchive(data)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.want {
+				t.Fatalf("ReadArchive error = %v, want %q", err, tt.want)
+			}
+
+			_, err = ReadBundle(data)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.want {
+				t.Fatalf("ReadBundle error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadDuplicateNames(t *testing.T) {
+	data := zipFiles(t, "pass.json", `{"serialNumber":"1"}`, "pass.json", `{"serialNumber":"2"}`)
+
+	if _, err := ReadArchive(data); err == nil || err.Error() != "Archive has more than one file pass.json" {
+		t.Errorf("ReadArchive error = %v", err)
+	}
+
+	if _, err := ReadBundle(data); err == nil || err.Error() != "Bundle has more than one file pass.json" {
+		t.Errorf("ReadBundle error = %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package passkit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// currencyCodes are the active ISO 4217 alphabetic codes.
+var currencyCodes = strings.Fields(`
+	AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB
+	BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF CLP CNY COP COU CRC CUP
+	CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ
+	GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW
+	KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR
+	MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN
+	PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC
+	SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD USN UYI UYU UYW UZS
+	VED VES VND VUV WST XAF XAG XAU XBA XBB XBC XBD XCD XCG XDR XOF XPD XPF XPT
+	XSU XTS XUA XXX YER ZAR ZMW ZWG
+`)
+
+var amountPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// IsValidAmount reports whether amount is a plain decimal number, such as
+// "-12.50", without exponent or grouping.
+func IsValidAmount(amount string) bool {
+	return amountPattern.MatchString(amount)
+}
+
+func IsValidCurrencyCode(code string) bool {
+	return slices.Contains(currencyCodes, code)
+}
+
+// Validate checks that the amount is a plain decimal number and the currency
+// code an ISO 4217 code.
+func (c *CurrencyAmount) Validate() error {
+	var errs []error
+
+	if c.Amount == "" {
+		errs = append(errs, errors.New("Amount can not be empty"))
+	} else if !IsValidAmount(c.Amount) {
+		errs = append(errs, fmt.Errorf("Amount %q is not a decimal number", c.Amount))
+	}
+
+	if c.CurrencyCode != "" && !IsValidCurrencyCode(c.CurrencyCode) {
+		errs = append(errs, fmt.Errorf("Unknown ISO 4217 currency code %q", c.CurrencyCode))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,60 @@
+package passkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateFormat is the W3C date format Wallet expects: seconds precision and an
+// explicit numeric offset.
+const DateFormat = "2006-01-02T15:04:05-07:00"
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+}
+
+// Date is a point in time that marshals in DateFormat. Older Wallet versions
+// reject the fractional seconds time.Time emits by default.
+type Date struct {
+	time.Time
+}
+
+func NewDate(t time.Time) *Date {
+	return &Date{Time: t}
+}
+
+func ParseDate(s string) (*Date, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return NewDate(t), nil
+		}
+	}
+
+	return nil, fmt.Errorf("Invalid date %q", s)
+}
+
+func (d Date) String() string {
+	return d.Truncate(time.Second).Format(DateFormat)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = *parsed
+
+	return nil
+}
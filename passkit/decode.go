@@ -0,0 +1,422 @@
+package passkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type DecodeMode int
+
+const (
+	// DecodeLenient accepts unknown and deprecated keys, keeping unknown ones
+	// in Pass.Extras so that they survive a round trip.
+	DecodeLenient DecodeMode = iota
+	// DecodeStrict rejects unknown keys, type mismatches and deprecated keys.
+	DecodeStrict
+)
+
+type DecodeIssueKind int
+
+const (
+	UnknownKey DecodeIssueKind = iota
+	TypeMismatch
+	DeprecatedKey
+)
+
+// DecodeIssue is a problem found at Path, a JSON path such as
+// "eventTicket.primaryFields[0].value". Dots, brackets and backslashes in
+// keys are escaped with a backslash.
+type DecodeIssue struct {
+	Kind    DecodeIssueKind
+	Path    string
+	Message string
+}
+
+func (i DecodeIssue) Error() string {
+	return i.Path + ": " + i.Message
+}
+
+// DecodeError lists every issue found while decoding a pass.
+type DecodeError struct {
+	Issues []DecodeIssue
+}
+
+func (e *DecodeError) Error() string {
+	msgs := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		msgs[i] = issue.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// deprecatedKeys maps top-level keys Wallet has replaced to their
+// replacement. They are only reported when the replacement is missing, as
+// the legacy key is still emitted for older devices. relevantDate is not
+// listed: on its own it is still the only date devices before iOS 18 read.
+var deprecatedKeys = map[string]string{
+	"barcode": "barcodes",
+}
+
+var (
+	semanticTagsType = reflect.TypeOf(SemanticTags{})
+	unmarshalerType  = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+	// walkedTypes decode themselves only to convert between shapes, so their
+	// keys are still checked one by one.
+	walkedTypes = map[reflect.Type]bool{
+		reflect.TypeOf(PassFieldContent{}): true,
+		semanticTagsType:                   true,
+	}
+
+	// oneOrManySemanticKeys are the array tags SemanticTags also accepts as
+	// a single value.
+	oneOrManySemanticKeys = map[string]bool{"artistIDs": true, "seats": true, "wifiAccess": true}
+)
+
+// DecodePass decodes a pass.json. In strict mode any issue fails the decode
+// with a *DecodeError; in lenient mode only type mismatches do.
+func DecodePass(data []byte, mode DecodeMode) (*Pass, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	d := &passDecoder{extras: make(map[string]json.RawMessage)}
+	d.walk("", tree, reflect.TypeOf(Pass{}), false)
+
+	if obj, ok := tree.(map[string]any); ok {
+		for key, replacement := range deprecatedKeys {
+			if _, ok := obj[key]; !ok {
+				continue
+			}
+			if _, ok := obj[replacement]; !ok {
+				d.add(DeprecatedKey, key, fmt.Sprintf("deprecated, use %s", replacement))
+			}
+		}
+	}
+
+	slices.SortStableFunc(d.issues, func(a, b DecodeIssue) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	var failed []DecodeIssue
+	for _, issue := range d.issues {
+		if mode == DecodeStrict || issue.Kind == TypeMismatch {
+			failed = append(failed, issue)
+		}
+	}
+
+	if len(failed) > 0 {
+		return nil, &DecodeError{Issues: failed}
+	}
+
+	var p Pass
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+
+	if len(d.extras) > 0 {
+		p.Extras = d.extras
+	}
+
+	return &p, nil
+}
+
+type passDecoder struct {
+	issues []DecodeIssue
+	extras map[string]json.RawMessage
+}
+
+func (d *passDecoder) add(kind DecodeIssueKind, path, msg string) {
+	d.issues = append(d.issues, DecodeIssue{Kind: kind, Path: path, Message: msg})
+}
+
+func jsonKind(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+
+	return "null"
+}
+
+// jsonFields maps the JSON keys of a struct to their types, including the
+// fields of embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					fields[k] = v
+				}
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+
+	return fields
+}
+
+func (d *passDecoder) walk(path string, v any, t reflect.Type, oneOrMany bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if v == nil || t.Kind() == reflect.Interface {
+		return
+	}
+
+	if !walkedTypes[t] && reflect.PointerTo(t).Implements(unmarshalerType) {
+		raw, _ := json.Marshal(v)
+		if err := json.Unmarshal(raw, reflect.New(t).Interface()); err != nil {
+			d.add(TypeMismatch, path, err.Error())
+		}
+		return
+	}
+
+	mismatch := func(want string) {
+		d.add(TypeMismatch, path, fmt.Sprintf("expected %s, got %s", want, jsonKind(v)))
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			mismatch("object")
+			return
+		}
+
+		fields := jsonFields(t)
+		for key, val := range obj {
+			sub := escapeKey(key)
+			if path != "" {
+				sub = path + "." + sub
+			}
+
+			ft, ok := fields[key]
+			if !ok {
+				msg := "unknown key"
+				if s := suggestKey(key, fields); s != "" {
+					msg += fmt.Sprintf(", did you mean %q?", s)
+				}
+				d.add(UnknownKey, sub, msg)
+				d.extras[sub], _ = json.Marshal(val)
+				continue
+			}
+
+			d.walk(sub, val, ft, t == semanticTagsType && oneOrManySemanticKeys[key])
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := v.([]any)
+		if !ok {
+			if oneOrMany {
+				// SemanticTags decodes a single value as a one element array.
+				d.walk(path+"[0]", v, t.Elem(), false)
+				return
+			}
+			mismatch("array")
+			return
+		}
+
+		for i, el := range arr {
+			d.walk(fmt.Sprintf("%s[%d]", path, i), el, t.Elem(), false)
+		}
+	case reflect.Map:
+		if _, ok := v.(map[string]any); !ok {
+			mismatch("object")
+		}
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			mismatch("string")
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			mismatch("boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := v.(json.Number)
+		if !ok {
+			mismatch("integer")
+			return
+		}
+
+		val := reflect.New(t).Elem()
+		if err := json.Unmarshal([]byte(n), val.Addr().Interface()); err != nil {
+			d.add(TypeMismatch, path, fmt.Sprintf("%s is not a valid %s", n, t.Kind()))
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := v.(json.Number); !ok {
+			mismatch("number")
+		}
+	}
+}
+
+// suggestKey returns the known key closest to a misspelt one, if any is
+// within two edits.
+func suggestKey(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for name := range fields {
+		if dist := editDistance(strings.ToLower(key), strings.ToLower(name)); dist < bestDist || (dist == bestDist && name < best) {
+			best, bestDist = name, dist
+		}
+	}
+
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+var keyEscaper = strings.NewReplacer(`\`, `\\`, `.`, `\.`, `[`, `\[`, `]`, `\]`)
+
+// escapeKey escapes an object key for use in a JSON path.
+func escapeKey(key string) string {
+	return keyEscaper.Replace(key)
+}
+
+// pathSegment is an object key or, if index is not negative, an array
+// index of a JSON path.
+type pathSegment struct {
+	key   string
+	index int
+}
+
+// parsePath splits a JSON path built by passDecoder into its segments.
+func parsePath(path string) []pathSegment {
+	var (
+		segments []pathSegment
+		key      strings.Builder
+	)
+
+	// Paths start with a key, and every dot starts another one.
+	inKey := true
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '\\' && i+1 < len(path):
+			i++
+			key.WriteByte(path[i])
+		case c == '.' || c == '[':
+			if inKey {
+				segments = append(segments, pathSegment{key: key.String(), index: -1})
+				key.Reset()
+			}
+			inKey = c == '.'
+			if c == '[' {
+				end := strings.IndexByte(path[i:], ']')
+				if end < 0 {
+					return segments
+				}
+				idx, err := strconv.Atoi(path[i+1 : i+end])
+				if err != nil {
+					return segments
+				}
+				segments = append(segments, pathSegment{index: idx})
+				i += end
+			}
+		default:
+			key.WriteByte(c)
+		}
+	}
+
+	if inKey {
+		segments = append(segments, pathSegment{key: key.String(), index: -1})
+	}
+
+	return segments
+}
+
+// mergeExtras adds the unknown keys kept by a lenient decode back into the
+// encoded pass.
+func mergeExtras(data []byte, extras map[string]json.RawMessage) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	for path, raw := range extras {
+		segments := parsePath(path)
+		node := tree
+		for i, seg := range segments {
+			last := i == len(segments)-1
+			if seg.index >= 0 {
+				arr, ok := node.([]any)
+				if !ok || seg.index >= len(arr) {
+					break
+				}
+				node = arr[seg.index]
+				continue
+			}
+
+			obj, ok := node.(map[string]any)
+			if !ok {
+				break
+			}
+
+			if last {
+				if _, exists := obj[seg.key]; !exists {
+					obj[seg.key] = raw
+				}
+				break
+			}
+			node = obj[seg.key]
+		}
+	}
+
+	return json.Marshal(tree)
+}
@@ -0,0 +1,181 @@
+package passkit
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const decodeTestPass = `{
+	"formatVersion": 1,
+	"passTypeIdentifier": "pass.com.example",
+	"serialNumber": "ABC123",
+	"teamIdentifier": "ABCDE12345",
+	"organizationName": "Example",
+	"description": "Event ticket",
+	%s
+}`
+
+func decodeTestJSON(extra string) []byte {
+	return []byte(strings.Replace(decodeTestPass, "%s", extra, 1))
+}
+
+func TestDecodePassExtrasRoundTrip(t *testing.T) {
+	data := decodeTestJSON(`"eventTicket": {
+		"primaryFields": [{"key": "event", "value": "Concert", "x.y": 1, "a[0]": "b", "back\\slash": true}]
+	},
+	"com.example.custom": {"nested": [1, 2]}`)
+
+	p, err := DecodePass(data, DecodeLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPaths := []string{
+		`com\.example\.custom`,
+		`eventTicket.primaryFields[0].a\[0\]`,
+		`eventTicket.primaryFields[0].back\\slash`,
+		`eventTicket.primaryFields[0].x\.y`,
+	}
+	for _, path := range wantPaths {
+		if _, ok := p.Extras[path]; !ok {
+			t.Errorf("extras have no %s: %v", path, p.Extras)
+		}
+	}
+
+	out, err := p.ToJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Custom      json.RawMessage `json:"com.example.custom"`
+		EventTicket struct {
+			PrimaryFields []map[string]any `json:"primaryFields"`
+		} `json:"eventTicket"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got.Custom) != `{"nested":[1,2]}` {
+		t.Errorf("custom key = %s", got.Custom)
+	}
+
+	field := got.EventTicket.PrimaryFields[0]
+	for key, want := range map[string]any{"x.y": 1.0, "a[0]": "b", `back\slash`: true} {
+		if field[key] != want {
+			t.Errorf("field key %q = %v, want %v", key, field[key], want)
+		}
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []pathSegment
+	}{
+		{path: "description", want: []pathSegment{{key: "description", index: -1}}},
+		{
+			path: "eventTicket.primaryFields[10].value",
+			want: []pathSegment{{key: "eventTicket", index: -1}, {key: "primaryFields", index: -1}, {index: 10}, {key: "value", index: -1}},
+		},
+		{path: `a\.b.c`, want: []pathSegment{{key: "a.b", index: -1}, {key: "c", index: -1}}},
+		{path: `\[0\][1]`, want: []pathSegment{{key: "[0]", index: -1}, {index: 1}}},
+		{path: `a.`, want: []pathSegment{{key: "a", index: -1}, {key: "", index: -1}}},
+	}
+
+	for _, tt := range tests {
+		if got := parsePath(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	for _, key := range []string{"plain", "x.y", "a[0]", `back\slash`, `.[]\`} {
+		if got := parsePath(escapeKey(key)); len(got) != 1 || got[0].key != key {
+			t.Errorf("escaped %q parses as %v", key, got)
+		}
+	}
+}
+
+func TestDecodePassStrict(t *testing.T) {
+	tests := []struct {
+		name   string
+		extra  string
+		issues []string
+	}{
+		{
+			name:  "relevantDate alone",
+			extra: `"eventTicket": {}, "relevantDate": "2024-06-20T19:00:00-07:00"`,
+		},
+		{
+			name:  "relevantDate with relevantDates",
+			extra: `"eventTicket": {}, "relevantDate": "2024-06-20T19:00:00-07:00", "relevantDates": [{"date": "2024-06-20T19:00:00-07:00"}]`,
+		},
+		{
+			name:   "barcode alone",
+			extra:  `"eventTicket": {}, "barcode": {"format": "PKBarcodeFormatQR", "message": "A", "messageEncoding": "iso-8859-1"}`,
+			issues: []string{"barcode: deprecated, use barcodes"},
+		},
+		{
+			name:   "unknown key",
+			extra:  `"eventTicket": {"primaryFeilds": []}`,
+			issues: []string{`eventTicket.primaryFeilds: unknown key, did you mean "primaryFields"?`},
+		},
+		{
+			name:   "single semantic performer name",
+			extra:  `"eventTicket": {}, "semantics": {"performerNames": "Foo"}`,
+			issues: []string{"semantics.performerNames: expected array, got string"},
+		},
+		{
+			name:  "single semantic seat",
+			extra: `"eventTicket": {}, "semantics": {"seats": {"seatNumber": "1"}}`,
+		},
+		{
+			name:   "type mismatch",
+			extra:  `"eventTicket": {"primaryFields": {}}`,
+			issues: []string{"eventTicket.primaryFields: expected array, got object"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodePass(decodeTestJSON(tt.extra), DecodeStrict)
+			if len(tt.issues) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("error = %v, want a *DecodeError", err)
+			}
+
+			var got []string
+			for _, issue := range decodeErr.Issues {
+				got = append(got, issue.Error())
+			}
+			if !reflect.DeepEqual(got, tt.issues) {
+				t.Errorf("issues = %q, want %q", got, tt.issues)
+			}
+		})
+	}
+}
+
+func TestDecodePassSemanticArrayMismatch(t *testing.T) {
+	_, err := DecodePass(decodeTestJSON(`"eventTicket": {}, "semantics": {"performerNames": "Foo"}`), DecodeLenient)
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("error = %v, want a *DecodeError", err)
+	}
+
+	want := []DecodeIssue{{Kind: TypeMismatch, Path: "semantics.performerNames", Message: "expected array, got string"}}
+	if !reflect.DeepEqual(decodeErr.Issues, want) {
+		t.Errorf("issues = %v, want %v", decodeErr.Issues, want)
+	}
+}
@@ -0,0 +1,296 @@
+package passkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChangeKind is how a value differs between two versions of a pass.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeModified ChangeKind = "modified"
+	ChangeMoved    ChangeKind = "moved"
+	ChangeRemoved  ChangeKind = "removed"
+)
+
+// fieldGroups are the keys of the field arrays in a pass style dictionary.
+var fieldGroups = []string{"headerFields", "primaryFields", "secondaryFields", "auxiliaryFields", "backFields", "additionalInfoFields"}
+
+// Change is a single difference between two versions of a pass.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+
+	// Path is the JSON path of the value in the new pass, or in the old one
+	// if it was removed, such as eventTicket.primaryFields[0].value.
+	Path string `json:"path"`
+
+	// FieldKey is the key of the pass field the change belongs to, if any.
+	// Fields are matched by key, so a field that moves to another group or
+	// style is reported as moved rather than removed and added. A field that
+	// only changes position within its group, such as when an earlier field
+	// is removed, is not reported.
+	FieldKey string `json:"fieldKey,omitempty"`
+
+	// Old and New are the JSON values before and after, nil when absent. For
+	// moved fields they are the old and new paths.
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+
+	// Notifies is set for value changes of fields with a changeMessage, which
+	// Wallet shows on the lock screen as Notification.
+	Notifies     bool   `json:"notifies,omitempty"`
+	Notification string `json:"notification,omitempty"`
+}
+
+type diffField struct {
+	// group is the path of the field array, such as eventTicket.backFields.
+	group   string
+	path    string
+	content PassFieldContent
+	tree    any
+}
+
+// toTree decodes the JSON encoding of v into maps, slices and json.Number.
+func toTree(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+func rawJSON(v any) json.RawMessage {
+	data, _ := json.Marshal(v)
+
+	return data
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// diffTree appends the differences between two decoded JSON values.
+func diffTree(path string, before, after any, changes []Change) []Change {
+	switch o := before.(type) {
+	case map[string]any:
+		n, ok := after.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(o)+len(n))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		for k := range n {
+			if _, ok := o[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			ov, inOld := o[k]
+			nv, inNew := n[k]
+			switch {
+			case !inOld:
+				changes = append(changes, Change{Kind: ChangeAdded, Path: joinPath(path, k), New: rawJSON(nv)})
+			case !inNew:
+				changes = append(changes, Change{Kind: ChangeRemoved, Path: joinPath(path, k), Old: rawJSON(ov)})
+			default:
+				changes = diffTree(joinPath(path, k), ov, nv, changes)
+			}
+		}
+
+		return changes
+	case []any:
+		n, ok := after.([]any)
+		if !ok {
+			break
+		}
+
+		for i := 0; i < max(len(o), len(n)); i++ {
+			elem := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(o):
+				changes = append(changes, Change{Kind: ChangeAdded, Path: elem, New: rawJSON(n[i])})
+			case i >= len(n):
+				changes = append(changes, Change{Kind: ChangeRemoved, Path: elem, Old: rawJSON(o[i])})
+			default:
+				changes = diffTree(elem, o[i], n[i], changes)
+			}
+		}
+
+		return changes
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		changes = append(changes, Change{Kind: ChangeModified, Path: path, Old: rawJSON(before), New: rawJSON(after)})
+	}
+
+	return changes
+}
+
+// diffFields lists the fields of the pass in display order, with their JSON
+// paths.
+func (p *Pass) diffFields() ([]diffField, error) {
+	pf := p.passFields()
+	if pf == nil {
+		return nil, nil
+	}
+
+	groups := [][]PassFieldContent{pf.HeaderFields, pf.PrimaryFields, pf.SecondaryFields, pf.AuxiliaryFields, pf.BackFields, nil}
+	if p.EventTicket != nil {
+		groups[5] = p.EventTicket.AdditionalInfoFields
+	}
+
+	var fields []diffField
+	for g, group := range groups {
+		for i, f := range group {
+			tree, err := toTree(f)
+			if err != nil {
+				return nil, err
+			}
+
+			group := fmt.Sprintf("%s.%s", p.Style(), fieldGroups[g])
+			path := fmt.Sprintf("%s[%d]", group, i)
+			fields = append(fields, diffField{group: group, path: path, content: f, tree: tree})
+		}
+	}
+
+	return fields, nil
+}
+
+// diffTreeWithoutFields decodes the pass leaving out the field groups, which
+// Diff compares by key.
+func (p *Pass) diffTreeWithoutFields() (any, error) {
+	tree, err := toTree(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if style, ok := tree.(map[string]any)[string(p.Style())].(map[string]any); ok {
+		for _, g := range fieldGroups {
+			delete(style, g)
+		}
+	}
+
+	return tree, nil
+}
+
+// identity is the key of the field, or its path if it has none.
+func (f diffField) identity() string {
+	if f.content.Key == "" {
+		return "@" + f.path
+	}
+
+	return f.content.Key
+}
+
+// Diff reports how newPass differs from oldPass, as Wallet would see the
+// update. Fields are compared by key and their value changes are flagged with
+// the notification their changeMessage produces. The changes are returned
+// even when the error lists change messages of newPass that lack the %@
+// placeholder.
+func Diff(oldPass, newPass *Pass) ([]Change, error) {
+	if oldPass == nil || newPass == nil {
+		return nil, errors.New("Pass can not be nil")
+	}
+
+	oldTree, err := oldPass.diffTreeWithoutFields()
+	if err != nil {
+		return nil, err
+	}
+
+	newTree, err := newPass.diffTreeWithoutFields()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffTree("", oldTree, newTree, nil)
+
+	oldFields, err := oldPass.diffFields()
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := newPass.diffFields()
+	if err != nil {
+		return nil, err
+	}
+
+	oldByKey := make(map[string]diffField, len(oldFields))
+	for _, f := range oldFields {
+		oldByKey[f.identity()] = f
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(newFields))
+	for _, nf := range newFields {
+		key := nf.identity()
+		seen[key] = true
+
+		if err := nf.content.validateChangeMessage(); err != nil {
+			errs = append(errs, err)
+		}
+
+		of, ok := oldByKey[key]
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeAdded, Path: nf.path, FieldKey: nf.content.Key, New: rawJSON(nf.tree)})
+			continue
+		}
+
+		if of.group != nf.group {
+			changes = append(changes, Change{Kind: ChangeMoved, Path: nf.path, FieldKey: nf.content.Key, Old: rawJSON(of.path), New: rawJSON(nf.path)})
+		}
+
+		for _, c := range diffTree(nf.path, of.tree, nf.tree, nil) {
+			c.FieldKey = nf.content.Key
+			if c.Path == nf.path+".value" && nf.content.ChangeMessage != "" && nf.content.Value != nil {
+				c.Notifies = true
+				c.Notification = strings.ReplaceAll(nf.content.ChangeMessage, "%@", nf.content.Value.String())
+			}
+			changes = append(changes, c)
+		}
+	}
+
+	for _, of := range oldFields {
+		if !seen[of.identity()] {
+			changes = append(changes, Change{Kind: ChangeRemoved, Path: of.path, FieldKey: of.content.Key, Old: rawJSON(of.tree)})
+		}
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// Notifications returns the lock screen messages the changes produce.
+func Notifications(changes []Change) []string {
+	var messages []string
+	for _, c := range changes {
+		if c.Notifies {
+			messages = append(messages, c.Notification)
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,109 @@
+package passkit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decodeDiffPass(t *testing.T, extra string) *Pass {
+	t.Helper()
+
+	p, err := DecodePass(decodeTestJSON(extra), DecodeLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestDiff(t *testing.T) {
+	decode := func(extra string) *Pass {
+		return decodeDiffPass(t, extra)
+	}
+
+	oldPass := decode(`"logoText": "Old", "eventTicket": {
+		"primaryFields": [{"key": "gate", "value": "A1", "changeMessage": "Gate changed to %@"}],
+		"secondaryFields": [{"key": "seat", "value": "12"}, {"key": "row", "value": "3"}]
+	}`)
+	newPass := decode(`"eventTicket": {
+		"primaryFields": [{"key": "seat", "value": "12"}],
+		"secondaryFields": [{"key": "gate", "value": "B2", "changeMessage": "Gate changed to %@"}, {"key": "door", "value": "4", "changeMessage": "Door"}]
+	}`)
+
+	changes, err := Diff(oldPass, newPass)
+	if err == nil || err.Error() != `Field "door": change message must contain the %@ placeholder` {
+		t.Errorf("error = %v", err)
+	}
+
+	type change struct {
+		kind         ChangeKind
+		path, key    string
+		notification string
+	}
+	var got []change
+	for _, c := range changes {
+		got = append(got, change{c.Kind, c.Path, c.FieldKey, c.Notification})
+	}
+
+	want := []change{
+		{kind: ChangeRemoved, path: "logoText"},
+		{kind: ChangeMoved, path: "eventTicket.primaryFields[0]", key: "seat"},
+		{kind: ChangeMoved, path: "eventTicket.secondaryFields[0]", key: "gate"},
+		{kind: ChangeModified, path: "eventTicket.secondaryFields[0].value", key: "gate", notification: "Gate changed to B2"},
+		{kind: ChangeAdded, path: "eventTicket.secondaryFields[1]", key: "door"},
+		{kind: ChangeRemoved, path: "eventTicket.secondaryFields[1]", key: "row"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes =\n%v\nwant\n%v", got, want)
+	}
+
+	if got := Notifications(changes); !reflect.DeepEqual(got, []string{"Gate changed to B2"}) {
+		t.Errorf("notifications = %q", got)
+	}
+
+	if changes, err := Diff(oldPass, oldPass); err != nil || len(changes) != 0 {
+		t.Errorf("diffing a pass with itself: %v, %v", changes, err)
+	}
+}
+
+func TestDiffFieldPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []Change
+	}{
+		{
+			name: "style change",
+			old:  `"eventTicket": {"primaryFields": [{"key": "a", "value": "1"}], "secondaryFields": [{"key": "b", "value": "2"}]}`,
+			new:  `"coupon": {"primaryFields": [{"key": "a", "value": "1"}], "secondaryFields": [{"key": "b", "value": "2"}]}`,
+			want: []Change{
+				{Kind: ChangeAdded, Path: "coupon", New: json.RawMessage(`{}`)},
+				{Kind: ChangeRemoved, Path: "eventTicket", Old: json.RawMessage(`{}`)},
+				{Kind: ChangeMoved, Path: "coupon.primaryFields[0]", FieldKey: "a", Old: json.RawMessage(`"eventTicket.primaryFields[0]"`), New: json.RawMessage(`"coupon.primaryFields[0]"`)},
+				{Kind: ChangeMoved, Path: "coupon.secondaryFields[0]", FieldKey: "b", Old: json.RawMessage(`"eventTicket.secondaryFields[0]"`), New: json.RawMessage(`"coupon.secondaryFields[0]"`)},
+			},
+		},
+		{
+			name: "field removed from a group",
+			old:  `"eventTicket": {"secondaryFields": [{"key": "a", "value": "1"}, {"key": "b", "value": "2"}, {"key": "c", "value": "3"}]}`,
+			new:  `"eventTicket": {"secondaryFields": [{"key": "a", "value": "1"}, {"key": "c", "value": "3"}]}`,
+			want: []Change{
+				{Kind: ChangeRemoved, Path: "eventTicket.secondaryFields[1]", FieldKey: "b", Old: json.RawMessage(`{"key":"b","value":"2"}`)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff(decodeDiffPass(t, tt.old), decodeDiffPass(t, tt.new))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(changes, tt.want) {
+				t.Errorf("changes =\n%+v\nwant\n%+v", changes, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package passkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type fieldValueKind int
+
+const (
+	fieldValueString fieldValueKind = iota
+	fieldValueNumber
+	fieldValueDate
+)
+
+// FieldValue is the value of a pass field. Wallet only applies number and
+// currency formatting to JSON numbers and date formatting to W3C date
+// strings, so FieldValue keeps track of which one it holds.
+type FieldValue struct {
+	kind   fieldValueKind
+	str    string
+	number float64
+	date   Date
+}
+
+func StringValue(s string) *FieldValue {
+	return &FieldValue{kind: fieldValueString, str: s}
+}
+
+func NumberValue(n float64) *FieldValue {
+	return &FieldValue{kind: fieldValueNumber, number: n}
+}
+
+func DateValue(t time.Time) *FieldValue {
+	return &FieldValue{kind: fieldValueDate, date: Date{Time: t}}
+}
+
+func (f *PassFieldContent) SetDateValue(t time.Time) {
+	f.Value = DateValue(t)
+}
+
+func (v FieldValue) IsString() bool {
+	return v.kind == fieldValueString
+}
+
+func (v FieldValue) IsNumber() bool {
+	return v.kind == fieldValueNumber
+}
+
+func (v FieldValue) IsDate() bool {
+	return v.kind == fieldValueDate
+}
+
+func (v FieldValue) Number() float64 {
+	return v.number
+}
+
+func (v FieldValue) Date() time.Time {
+	return v.date.Time
+}
+
+func (v FieldValue) String() string {
+	switch v.kind {
+	case fieldValueNumber:
+		return strconv.FormatFloat(v.number, 'f', -1, 64)
+	case fieldValueDate:
+		return v.date.String()
+	}
+
+	return v.str
+}
+
+func (v FieldValue) MarshalJSON() ([]byte, error) {
+	if v.kind == fieldValueNumber {
+		if math.IsInf(v.number, 0) || math.IsNaN(v.number) {
+			return nil, fmt.Errorf("Field value %v is not a valid JSON number", v.number)
+		}
+
+		return []byte(v.String()), nil
+	}
+
+	return json.Marshal(v.String())
+}
+
+// UnmarshalJSON decodes JSON numbers as numbers and keeps strings as is.
+// Whether a string is a date depends on the field's styles, so
+// PassFieldContent decides that.
+func (v *FieldValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] != '"' {
+		var n float64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("Field value must be a string, number or date: %w", err)
+		}
+
+		*v = *NumberValue(n)
+
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*v = *StringValue(s)
+
+	return nil
+}
+
+// UnmarshalJSON decodes the field, reading a string value as a date only when
+// the field has a date or time style. Wallet treats the value of any other
+// field as text, even if it looks like a date.
+func (f *PassFieldContent) UnmarshalJSON(data []byte) error {
+	type passFieldContent PassFieldContent
+	var content passFieldContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+
+	if v := content.Value; v != nil && v.IsString() && (content.DateStyle != "" || content.TimeStyle != "") {
+		if d, err := ParseDate(v.str); err == nil {
+			content.Value = &FieldValue{kind: fieldValueDate, date: *d}
+		}
+	}
+
+	*f = PassFieldContent(content)
+
+	return nil
+}
+
+// validateChangeMessage checks that the change message has the %@ Wallet
+// replaces with the new value.
+func (f *PassFieldContent) validateChangeMessage() error {
+	if f.ChangeMessage != "" && !strings.Contains(f.ChangeMessage, "%@") {
+		return fmt.Errorf("Field %q: change message must contain the %%@ placeholder", f.Key)
+	}
+
+	return nil
+}
+
+// Validate checks that the formatting keys of the field match the type of
+// its value.
+func (f *PassFieldContent) Validate() error {
+	if f.Key == "" {
+		return errors.New("Field key can not be empty")
+	}
+
+	if f.Value == nil {
+		return fmt.Errorf("Field %q: value can not be empty", f.Key)
+	}
+
+	errs := []error{f.validateChangeMessage()}
+	if !f.Value.IsNumber() {
+		if f.NumberStyle != "" {
+			errs = append(errs, fmt.Errorf("Field %q: number style can only be used with a number value", f.Key))
+		}
+
+		if f.CurrencyCode != "" {
+			errs = append(errs, fmt.Errorf("Field %q: currency code can only be used with a number value", f.Key))
+		}
+	}
+
+	if f.CurrencyCode != "" && !IsValidCurrencyCode(f.CurrencyCode) {
+		errs = append(errs, fmt.Errorf("Field %q: unknown ISO 4217 currency code %q", f.Key, f.CurrencyCode))
+	}
+
+	if !f.Value.IsDate() {
+		if f.DateStyle != "" {
+			errs = append(errs, fmt.Errorf("Field %q: date style can only be used with a date value", f.Key))
+		}
+
+		if f.TimeStyle != "" {
+			errs = append(errs, fmt.Errorf("Field %q: time style can only be used with a date value", f.Key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package passkit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPassFieldContentRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		isDate bool
+	}{
+		{"text that looks like a date", `{"key":"code","value":"2026-10-20T09:30:00-07:00"}`, false},
+		{"date with date style", `{"dateStyle":"PKDateStyleShort","key":"start","value":"2026-10-20T09:30:00-07:00"}`, true},
+		{"date with time style", `{"key":"start","timeStyle":"PKTimeStyleShort","value":"2026-10-20T09:30:00-07:00"}`, true},
+		{"styled text that is not a date", `{"dateStyle":"PKDateStyleShort","key":"start","value":"soon"}`, false},
+		{"large number", `{"key":"points","value":1000000000000000000000}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f PassFieldContent
+			if err := json.Unmarshal([]byte(tt.json), &f); err != nil {
+				t.Fatal(err)
+			}
+
+			if f.Value.IsDate() != tt.isDate {
+				t.Errorf("IsDate() = %v, want %v", f.Value.IsDate(), tt.isDate)
+			}
+
+			out, err := json.Marshal(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(out) != tt.json {
+				t.Errorf("round trip = %s, want %s", out, tt.json)
+			}
+		})
+	}
+}
+
+func TestFieldValueStringLargeNumber(t *testing.T) {
+	if got := NumberValue(1e21).String(); got != "1000000000000000000000" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,285 @@
+// Command schemagen writes the JSON Schema of pass.json. It takes the shape
+// of the schema from the passkit types by reflection and the enum values and
+// descriptions from the package source, so neither can drift from the code.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+type source struct {
+	enums        map[string][]string
+	descriptions map[string]string
+}
+
+func docText(groups ...*ast.CommentGroup) string {
+	for _, g := range groups {
+		if text := strings.TrimSpace(g.Text()); text != "" {
+			return strings.Join(strings.Fields(text), " ")
+		}
+	}
+
+	return ""
+}
+
+// parseSource collects the string constants of each named type and the doc
+// comments of types and struct fields in dir.
+func parseSource(dir string) (*source, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &source{enums: make(map[string][]string), descriptions: make(map[string]string)}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok {
+					continue
+				}
+
+				for _, spec := range gen.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						ident, ok := spec.Type.(*ast.Ident)
+						if gen.Tok != token.CONST || !ok {
+							continue
+						}
+
+						for _, v := range spec.Values {
+							lit, ok := v.(*ast.BasicLit)
+							if !ok || lit.Kind != token.STRING {
+								continue
+							}
+							s, _ := strconv.Unquote(lit.Value)
+							src.enums[ident.Name] = append(src.enums[ident.Name], s)
+						}
+					case *ast.TypeSpec:
+						doc := gen.Doc
+						if len(gen.Specs) > 1 {
+							doc = spec.Doc
+						}
+						if text := docText(doc); text != "" {
+							src.descriptions[spec.Name.Name] = text
+						}
+
+						st, ok := spec.Type.(*ast.StructType)
+						if !ok {
+							continue
+						}
+						for _, f := range st.Fields.List {
+							text := docText(f.Doc, f.Comment)
+							for _, name := range f.Names {
+								if text != "" {
+									src.descriptions[spec.Name.Name+"."+name.Name] = text
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return src, nil
+}
+
+type generator struct {
+	src  *source
+	defs map[string]any
+}
+
+var (
+	colorType      = reflect.TypeOf(passkit.Color{})
+	dateType       = reflect.TypeOf(passkit.Date{})
+	fieldValueType = reflect.TypeOf(passkit.FieldValue{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (g *generator) ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+func (g *generator) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case colorType:
+		return g.define(t, func() map[string]any {
+			return map[string]any{"type": "string", "description": "A CSS-style color, such as rgb(255, 255, 255) or #fff."}
+		})
+	case dateType:
+		return g.define(t, func() map[string]any {
+			return map[string]any{"type": "string", "format": "date-time"}
+		})
+	case fieldValueType:
+		return g.define(t, func() map[string]any {
+			return map[string]any{"type": []string{"string", "number"}}
+		})
+	case rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return map[string]any{}
+	case reflect.Struct:
+		return g.define(t, func() map[string]any { return g.object(t) })
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.String:
+		if values, ok := g.src.enums[t.Name()]; ok && t.PkgPath() != "" {
+			return g.define(t, func() map[string]any {
+				return map[string]any{"type": "string", "enum": values}
+			})
+		}
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+
+	log.Fatalf("schemagen: unsupported type %s", t)
+	return nil
+}
+
+// define adds the schema of a named type to $defs once and returns a
+// reference to it.
+func (g *generator) define(t reflect.Type, build func() map[string]any) map[string]any {
+	name := t.Name()
+	if _, ok := g.defs[name]; !ok {
+		g.defs[name] = nil
+		def := build()
+		if desc := g.src.descriptions[name]; desc != "" {
+			def["description"] = desc
+		}
+		g.defs[name] = def
+	}
+
+	return g.ref(name)
+}
+
+func (g *generator) object(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	g.fields(t, props, &required)
+	sort.Strings(required)
+
+	obj := map[string]any{"type": "object", "properties": props, "additionalProperties": false}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+
+	return obj
+}
+
+func (g *generator) fields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			g.fields(ft, props, required)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := g.schema(f.Type)
+		if desc := g.src.descriptions[t.Name()+"."+f.Name]; desc != "" {
+			// Siblings of $ref are allowed since draft 2019-09.
+			prop = mapsClone(prop)
+			prop["description"] = desc
+		}
+		props[name] = prop
+
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func mapsClone(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+
+	return out
+}
+
+// generate returns the schema of the passkit source in dir.
+func generate(dir string) ([]byte, error) {
+	src, err := parseSource(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{src: src, defs: make(map[string]any)}
+	root := g.schema(reflect.TypeOf(passkit.Pass{}))
+
+	schema := map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         "https://github.com/clevtech/apple-wallet-pass/passkit/pass.schema.json",
+		"title":       "pass.json",
+		"description": "The pass.json of an Apple Wallet pass.",
+		"$ref":        root["$ref"],
+		"$defs":       g.defs,
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+func main() {
+	dir := flag.String("dir", ".", "passkit source directory")
+	out := flag.String("o", "pass.schema.json", "output file")
+	flag.Parse()
+
+	data, err := generate(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestSchemaUpToDate(t *testing.T) {
+	data, err := generate("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	committed, err := os.ReadFile("../../pass.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, committed) {
+		t.Fatal("pass.schema.json is out of date, run go generate in passkit")
+	}
+}
+
+func TestSchemaPass(t *testing.T) {
+	data, err := generate("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]struct {
+				Description string `json:"description"`
+			} `json:"properties"`
+			Required []string `json:"required"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	pass := schema.Defs["Pass"]
+	if len(pass.Properties) == 0 {
+		t.Fatal("schema has no Pass properties")
+	}
+	for name, prop := range pass.Properties {
+		if prop.Description == "" {
+			t.Errorf("Pass property %s has no description", name)
+		}
+	}
+
+	for _, name := range []string{"description", "formatVersion", "organizationName", "passTypeIdentifier", "serialNumber", "teamIdentifier"} {
+		if !slices.Contains(pass.Required, name) {
+			t.Errorf("Pass property %s is not required", name)
+		}
+	}
+}
@@ -3,16 +3,50 @@ package passkit
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 )
 
+// BarcodeFormat is the symbology of a barcode.
 type BarcodeFormat string
+
+// DataDetectorType is a kind of data Wallet turns into a link on back fields.
 type DataDetectorType string
+
+// DateStyle is the style Wallet formats the date part of a date field value in.
 type DateStyle string
+
+// EventType is the kind of event an event ticket admits to.
+type EventType string
+
+// ImageRole is the name of an image in the pass bundle, without scale suffix and extension.
+type ImageRole string
+
+// NumberStyle is the style Wallet formats a number field value in.
 type NumberStyle string
+
+// PassengerCapability is a boarding privilege of the passenger.
+type PassengerCapability string
+
+// PassPersonalizationField is a piece of user information a reward enrollment pass asks for.
 type PassPersonalizationField string
+
+// PassStyle is the key of the pass style dictionary in pass.json.
+type PassStyle string
+
+// StyleScheme is a layout Wallet may use to show the pass.
+type StyleScheme string
+
+// TextAlignment is the alignment of a field's text on the front of the pass.
 type TextAlignment string
+
+// TimeStyle is the style Wallet formats the time part of a date field value in.
 type TimeStyle string
+
+// TransitSecurityProgram is an airport security screening program.
+type TransitSecurityProgram string
+
+// TransitType is the kind of transport a boarding pass is for.
 type TransitType string
 
 const (
@@ -32,16 +66,48 @@ const (
 	DateStyleLong   DateStyle = "PKDateStyleLong"
 	DateStyleFull   DateStyle = "PKDateStyleFull"
 
+	EventTypeGeneric         EventType = "PKEventTypeGeneric"
+	EventTypeLivePerformance EventType = "PKEventTypeLivePerformance"
+	EventTypeMovie           EventType = "PKEventTypeMovie"
+	EventTypeSports          EventType = "PKEventTypeSports"
+	EventTypeConference      EventType = "PKEventTypeConference"
+	EventTypeConvention      EventType = "PKEventTypeConvention"
+	EventTypeWorkshop        EventType = "PKEventTypeWorkshop"
+	EventTypeSocialGathering EventType = "PKEventTypeSocialGathering"
+
+	ImageRoleArtwork       ImageRole = "artwork"
+	ImageRoleBackground    ImageRole = "background"
+	ImageRoleFooter        ImageRole = "footer"
+	ImageRoleIcon          ImageRole = "icon"
+	ImageRoleLogo          ImageRole = "logo"
+	ImageRoleSecondaryLogo ImageRole = "secondaryLogo"
+	ImageRoleStrip         ImageRole = "strip"
+	ImageRoleThumbnail     ImageRole = "thumbnail"
+
 	NumberStyleDecimal    NumberStyle = "PKNumberStyleDecimal"
 	NumberStylePercent    NumberStyle = "PKNumberStylePercent"
 	NumberStyleScientific NumberStyle = "PKNumberStyleScientific"
 	NumberStyleSpellOut   NumberStyle = "PKNumberStyleSpellOut"
 
+	PassengerCapabilityPreboarding      PassengerCapability = "PKPassengerCapabilityPreboarding"
+	PassengerCapabilityPriorityBoarding PassengerCapability = "PKPassengerCapabilityPriorityBoarding"
+	PassengerCapabilityCarryon          PassengerCapability = "PKPassengerCapabilityCarryon"
+	PassengerCapabilityPersonalItem     PassengerCapability = "PKPassengerCapabilityPersonalItem"
+
 	PassPersonalizationFieldName         PassPersonalizationField = "PKPassPersonalizationFieldName"
 	PassPersonalizationFieldPostalCode   PassPersonalizationField = "PKPassPersonalizationFieldPostalCode"
 	PassPersonalizationFieldEmailAddress PassPersonalizationField = "PKPassPersonalizationFieldEmailAddress"
 	PassPersonalizationFieldPhoneNumber  PassPersonalizationField = "PKPassPersonalizationFieldPhoneNumber"
 
+	PassStyleBoardingPass PassStyle = "boardingPass"
+	PassStyleCoupon       PassStyle = "coupon"
+	PassStyleEventTicket  PassStyle = "eventTicket"
+	PassStyleGeneric      PassStyle = "generic"
+	PassStyleStoreCard    PassStyle = "storeCard"
+
+	StyleSchemeEventTicket       StyleScheme = "eventTicket"
+	StyleSchemePosterEventTicket StyleScheme = "posterEventTicket"
+
 	TextAlignmentLeft    TextAlignment = "PKTextAlignmentLeft"
 	TextAlignmentCenter  TextAlignment = "PKTextAlignmentCenter"
 	TextAlignmentRight   TextAlignment = "PKTextAlignmentRight"
@@ -53,6 +119,14 @@ const (
 	TimeStyleLong   TimeStyle = "PKTimeStyleLong"
 	TimeStyleFull   TimeStyle = "PKTimeStyleFull"
 
+	TransitSecurityProgramTSAPreCheck            TransitSecurityProgram = "PKTransitSecurityProgramTSAPreCheck"
+	TransitSecurityProgramTSAPreCheckTouchlessID TransitSecurityProgram = "PKTransitSecurityProgramTSAPreCheckTouchlessID"
+	TransitSecurityProgramOSS                    TransitSecurityProgram = "PKTransitSecurityProgramOSS"
+	TransitSecurityProgramITI                    TransitSecurityProgram = "PKTransitSecurityProgramITI"
+	TransitSecurityProgramITD                    TransitSecurityProgram = "PKTransitSecurityProgramITD"
+	TransitSecurityProgramGlobalEntry            TransitSecurityProgram = "PKTransitSecurityProgramGlobalEntry"
+	TransitSecurityProgramCLEAR                  TransitSecurityProgram = "PKTransitSecurityProgramCLEAR"
+
 	TransitTypeAir     TransitType = "PKTransitTypeAir"
 	TransitTypeBoat    TransitType = "PKTransitTypeBoat"
 	TransitTypeBus     TransitType = "PKTransitTypeBus"
@@ -60,39 +134,218 @@ const (
 	TransitTypeTrain   TransitType = "PKTransitTypeTrain"
 )
 
+// Pass is the top-level dictionary of pass.json.
 type Pass struct {
-	AppLaunchURL               string        `json:"appLaunchURL,omitempty"`
-	AssociatedStoreIdentifiers []int64       `json:"associatedStoreIdentifiers,omitempty"`
-	AuthenticationToken        string        `json:"authenticationToken,omitempty"`
-	BackgroundColor            string        `json:"backgroundColor,omitempty"`
-	Barcodes                   []Barcodes    `json:"barcodes,omitempty"`
-	Beacons                    []Beacons     `json:"beacons,omitempty"`
-	BoardingPass               *BoardingPass `json:"boardingPass,omitempty"`
-	Coupon                     *Coupon       `json:"coupon,omitempty"`
-	Description                string        `json:"description,omitempty"`
-	EventTicket                *EventTicket  `json:"eventTicket,omitempty"`
-	ExpirationDate             *time.Time    `json:"expirationDate,omitempty"`
-	ForegroundColor            string        `json:"foregroundColor,omitempty"`
-	FormatVersion              int64         `json:"formatVersion"`
-	Generic                    *Generic      `json:"generic,omitempty"`
-	GroupingIdentifier         string        `json:"groupingIdentifier,omitempty"`
-	LabelColor                 string        `json:"labelColor,omitempty"`
-	Locations                  []Locations   `json:"locations,omitempty"`
-	LogoText                   string        `json:"logoText,omitempty"`
-	MaxDistance                int64         `json:"maxDistance,omitempty"`
-	NFC                        *NFC          `json:"nfc,omitempty"`
-	OrganizationName           string        `json:"organizationName"`
-	PassTypeIdentifier         string        `json:"passTypeIdentifier"`
-	RelevantDate               *time.Time    `json:"relevantDate,omitempty"`
-	Semantics                  *SemanticTags `json:"semantics,omitempty"`
-	SerialNumber               string        `json:"serialNumber"`
-	SharingProhibited          bool          `json:"sharingProhibited,omitempty"`
-	StoreCard                  *StoreCard    `json:"storeCard,omitempty"`
-	SuppressStripShine         bool          `json:"suppressStripShine,omitempty"`
-	TeamIdentifier             string        `json:"teamIdentifier"`
-	UserInfo                   interface{}   `json:"userInfo,omitempty"`
-	Voided                     bool          `json:"voided,omitempty"`
-	WebServiceURL              string        `json:"webServiceURL,omitempty"`
+	// AppLaunchURL is passed to the associated app when it is opened from
+	// the pass.
+	AppLaunchURL string `json:"appLaunchURL,omitempty"`
+
+	// AssociatedStoreIdentifiers are the App Store IDs of the apps
+	// associated with the pass. Wallet offers the first one the device can
+	// run.
+	AssociatedStoreIdentifiers []int64 `json:"associatedStoreIdentifiers,omitempty"`
+
+	// AuthenticationToken is sent by devices to the web service, and must be
+	// at least 16 characters long.
+	AuthenticationToken string `json:"authenticationToken,omitempty"`
+
+	// BackgroundColor is the background color of the pass.
+	BackgroundColor *Color `json:"backgroundColor,omitempty"`
+
+	// Barcode is the barcode read by iOS 8 and earlier. ToJson fills it in
+	// from Barcodes when it is not set.
+	Barcode *Barcodes `json:"barcode,omitempty"`
+
+	// Barcodes lists barcodes in order of preference. Wallet shows the first
+	// one the device supports.
+	Barcodes []Barcodes `json:"barcodes,omitempty"`
+
+	// Beacons are Bluetooth Low Energy beacons near which the pass is
+	// relevant.
+	Beacons []Beacons `json:"beacons,omitempty"`
+
+	// BoardingPass holds the fields of a boarding pass. A pass sets exactly
+	// one of the style keys.
+	BoardingPass *BoardingPass `json:"boardingPass,omitempty"`
+
+	// Coupon holds the fields of a coupon.
+	Coupon *Coupon `json:"coupon,omitempty"`
+
+	// Description briefly describes the pass for accessibility features.
+	Description string `json:"description"`
+
+	// EventTicket holds the fields of an event ticket.
+	EventTicket *EventTicket `json:"eventTicket,omitempty"`
+
+	// EventLogoText is shown next to the logo of poster event tickets.
+	EventLogoText string `json:"eventLogoText,omitempty"`
+
+	// ExpirationDate is when the pass stops being valid.
+	ExpirationDate *Date `json:"expirationDate,omitempty"`
+
+	// Extras holds the keys a lenient DecodePass did not know, by JSON path,
+	// so that ToJson can write them back.
+	Extras map[string]json.RawMessage `json:"-"`
+
+	// FooterBackgroundColor is the background color of the footer of poster
+	// event tickets.
+	FooterBackgroundColor *Color `json:"footerBackgroundColor,omitempty"`
+
+	// ForegroundColor is the color of field values.
+	ForegroundColor *Color `json:"foregroundColor,omitempty"`
+
+	// FormatVersion is the version of the pass format, which must be 1.
+	FormatVersion int64 `json:"formatVersion"`
+
+	// Generic holds the fields of a generic pass.
+	Generic *Generic `json:"generic,omitempty"`
+
+	// GroupingIdentifier groups boarding passes and event tickets of the
+	// same pass type together in Wallet.
+	GroupingIdentifier string `json:"groupingIdentifier,omitempty"`
+
+	// LabelColor is the color of field labels.
+	LabelColor *Color `json:"labelColor,omitempty"`
+
+	// Locations are places near which the pass is relevant.
+	Locations []Locations `json:"locations,omitempty"`
+
+	// LogoText is shown next to the logo.
+	LogoText string `json:"logoText,omitempty"`
+
+	// MaxDistance is how close, in meters, the device must be to a location
+	// for the pass to be relevant.
+	MaxDistance int64 `json:"maxDistance,omitempty"`
+
+	// NFC is the payload sent to NFC readers, which needs an NFC enabled
+	// pass type certificate.
+	NFC *NFC `json:"nfc,omitempty"`
+
+	// OrganizationName is the name of the issuer, shown on the lock screen.
+	OrganizationName string `json:"organizationName"`
+
+	// PassTypeIdentifier is the Pass Type ID of the signing certificate.
+	PassTypeIdentifier string `json:"passTypeIdentifier"`
+
+	// PreferredStyleSchemes lists the styles the pass prefers, such as the
+	// poster event ticket, most preferred first.
+	PreferredStyleSchemes []StyleScheme `json:"preferredStyleSchemes,omitempty"`
+
+	// RelevantDate is when the pass is relevant on devices before iOS 18.
+	// ToJson fills it in from RelevantDates when it is not set.
+	RelevantDate *Date `json:"relevantDate,omitempty"`
+
+	// RelevantDates are the dates and intervals when the pass is relevant,
+	// in chronological order.
+	RelevantDates []RelevantDate `json:"relevantDates,omitempty"`
+
+	// Semantics is machine-readable metadata about the pass.
+	Semantics *SemanticTags `json:"semantics,omitempty"`
+
+	// SerialNumber identifies the pass among those of its pass type.
+	SerialNumber string `json:"serialNumber"`
+
+	// SharingProhibited hides the share button of the pass.
+	SharingProhibited bool `json:"sharingProhibited,omitempty"`
+
+	// StoreCard holds the fields of a store card.
+	StoreCard *StoreCard `json:"storeCard,omitempty"`
+
+	// SuppressHeaderDarkening keeps Wallet from darkening the header of
+	// poster event tickets.
+	SuppressHeaderDarkening bool `json:"suppressHeaderDarkening,omitempty"`
+
+	// SuppressStripShine removes the shine effect from the strip image.
+	SuppressStripShine bool `json:"suppressStripShine,omitempty"`
+
+	// TeamIdentifier is the team ID of the signing certificate.
+	TeamIdentifier string `json:"teamIdentifier"`
+
+	// UpcomingPassInformation lists the further events of a season or multi
+	// event ticket.
+	UpcomingPassInformation []UpcomingPassInformation `json:"upcomingPassInformation,omitempty"`
+
+	// UseAutomaticColors lets Wallet pick the colors of poster event tickets
+	// from their background image.
+	UseAutomaticColors bool `json:"useAutomaticColors,omitempty"`
+
+	// UserInfo is custom JSON for the associated app, ignored by Wallet.
+	UserInfo interface{} `json:"userInfo,omitempty"`
+
+	// Voided marks the pass as no longer usable, such as a redeemed coupon.
+	Voided bool `json:"voided,omitempty"`
+
+	// WebServiceURL is the base URL of the web service that updates the
+	// pass.
+	WebServiceURL string `json:"webServiceURL,omitempty"`
+}
+
+// NewPass returns an empty pass with the only format version Wallet accepts.
+func NewPass() *Pass {
+	return &Pass{FormatVersion: 1}
+}
+
+// newStylePass returns a pass with every required key set and the style
+// applied by setStyle, or all errors found doing so.
+func newStylePass(org, passTypeID, teamID, serial, description string, setStyle func(p *Pass) error) (*Pass, error) {
+	p := NewPass()
+
+	err := errors.Join(
+		p.SetOrganizationName(org),
+		p.SetPassTypeIdentifier(passTypeID),
+		p.SetTeamIdentifier(teamID),
+		p.SetSerialNumber(serial),
+		p.SetDescription(description),
+		setStyle(p),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// NewBoardingPassPass returns a valid boarding pass for transitType.
+func NewBoardingPassPass(transitType TransitType, org, passTypeID, teamID, serial, description string) (*Pass, error) {
+	return newStylePass(org, passTypeID, teamID, serial, description, func(p *Pass) error {
+		if !isValidTransitType(transitType) {
+			return fmt.Errorf("Unknown transit type %q", transitType)
+		}
+
+		return p.SetBoardingPass(NewBoardingPass(transitType))
+	})
+}
+
+// NewCouponPass returns a valid coupon pass.
+func NewCouponPass(org, passTypeID, teamID, serial, description string) (*Pass, error) {
+	return newStylePass(org, passTypeID, teamID, serial, description, func(p *Pass) error {
+		return p.SetCoupon(NewCoupon())
+	})
+}
+
+// NewEventTicketPass returns a valid event ticket pass.
+func NewEventTicketPass(org, passTypeID, teamID, serial, description string) (*Pass, error) {
+	return newStylePass(org, passTypeID, teamID, serial, description, func(p *Pass) error {
+		return p.SetEventTicket(NewEventTicket())
+	})
+}
+
+// NewGenericPass returns a valid generic pass.
+func NewGenericPass(org, passTypeID, teamID, serial, description string) (*Pass, error) {
+	return newStylePass(org, passTypeID, teamID, serial, description, func(p *Pass) error {
+		return p.SetGeneric(NewGeneric())
+	})
+}
+
+// NewStoreCardPass returns a valid store card pass.
+func NewStoreCardPass(org, passTypeID, teamID, serial, description string) (*Pass, error) {
+	return newStylePass(org, passTypeID, teamID, serial, description, func(p *Pass) error {
+		return p.SetStoreCard(NewStoreCard())
+	})
 }
 
 func (p *Pass) SetAppLaunchURL(url string) error {
@@ -130,17 +383,25 @@ func (p *Pass) SetBackgroundColor(color string) error {
 		return errors.New("Background color can not be empty")
 	}
 
-	p.BackgroundColor = color
+	c, err := ParseColor(color)
+	if err != nil {
+		return err
+	}
+
+	p.BackgroundColor = c
 
 	return nil
 }
 
+// SetBarcodes replaces the barcodes. It clears Barcode, so that ToJson
+// derives it for older devices from the new barcodes.
 func (p *Pass) SetBarcodes(barcodes []Barcodes) error {
 	if len(barcodes) == 0 {
 		return errors.New("Barcodes can not be empty")
 	}
 
 	p.Barcodes = barcodes
+	p.Barcode = nil
 
 	return nil
 }
@@ -195,12 +456,37 @@ func (p *Pass) SetEventTicket(eventTicket *EventTicket) error {
 	return nil
 }
 
+func (p *Pass) SetEventLogoText(text string) error {
+	if text == "" {
+		return errors.New("Event logo text can not be empty")
+	}
+
+	p.EventLogoText = text
+
+	return nil
+}
+
 func (p *Pass) SetExpirationDate(date *time.Time) error {
 	if date == nil {
 		return errors.New("Expiration date can not be empty")
 	}
 
-	p.ExpirationDate = date
+	p.ExpirationDate = NewDate(*date)
+
+	return nil
+}
+
+func (p *Pass) SetFooterBackgroundColor(color string) error {
+	if color == "" {
+		return errors.New("Footer background color can not be empty")
+	}
+
+	c, err := ParseColor(color)
+	if err != nil {
+		return err
+	}
+
+	p.FooterBackgroundColor = c
 
 	return nil
 }
@@ -210,7 +496,12 @@ func (p *Pass) SetForegroundColor(color string) error {
 		return errors.New("Foreground color can not be empty")
 	}
 
-	p.ForegroundColor = color
+	c, err := ParseColor(color)
+	if err != nil {
+		return err
+	}
+
+	p.ForegroundColor = c
 
 	return nil
 }
@@ -250,7 +541,12 @@ func (p *Pass) SetLabelColor(color string) error {
 		return errors.New("Label color can not be empty")
 	}
 
-	p.LabelColor = color
+	c, err := ParseColor(color)
+	if err != nil {
+		return err
+	}
+
+	p.LabelColor = c
 
 	return nil
 }
@@ -315,12 +611,35 @@ func (p *Pass) SetPassTypeIdentifier(identifier string) error {
 	return nil
 }
 
+func (p *Pass) SetPreferredStyleSchemes(schemes []StyleScheme) error {
+	if len(schemes) == 0 {
+		return errors.New("Preferred style schemes can not be empty")
+	}
+
+	p.PreferredStyleSchemes = schemes
+
+	return nil
+}
+
 func (p *Pass) SetRelevantDate(date *time.Time) error {
 	if date == nil {
 		return errors.New("Relevant date can not be empty")
 	}
 
-	p.RelevantDate = date
+	p.RelevantDate = NewDate(*date)
+
+	return nil
+}
+
+// SetRelevantDates replaces the relevant dates. It clears RelevantDate, so
+// that ToJson derives it for older devices from the new dates.
+func (p *Pass) SetRelevantDates(dates []RelevantDate) error {
+	if len(dates) == 0 {
+		return errors.New("Relevant dates can not be empty")
+	}
+
+	p.RelevantDates = dates
+	p.RelevantDate = nil
 
 	return nil
 }
@@ -361,6 +680,12 @@ func (p *Pass) SetStoreCard(storeCard *StoreCard) error {
 	return nil
 }
 
+func (p *Pass) SetSuppressHeaderDarkening(suppress bool) error {
+	p.SuppressHeaderDarkening = suppress
+
+	return nil
+}
+
 func (p *Pass) SetSuppressStripShine(suppress bool) error {
 	p.SuppressStripShine = suppress
 
@@ -377,15 +702,40 @@ func (p *Pass) SetTeamIdentifier(identifier string) error {
 	return nil
 }
 
-// func (p *Pass) SetUserInfo(userInfo []UserInfo) error {
-// 	if len(userInfo) == 0 {
-// 		return errors.New("User infos can not be empty")
-// 	}
+func (p *Pass) SetUpcomingPassInformation(entries []UpcomingPassInformation) error {
+	if len(entries) == 0 {
+		return errors.New("Upcoming pass information can not be empty")
+	}
+
+	p.UpcomingPassInformation = entries
+
+	return nil
+}
+
+func (p *Pass) SetUseAutomaticColors(automatic bool) error {
+	p.UseAutomaticColors = automatic
 
-// 	p.UserInfo = userInfo
+	return nil
+}
+
+func (p *Pass) SetUserInfo(userInfo any) error {
+	if userInfo == nil {
+		return errors.New("User info can not be empty")
+	}
 
-// 	return nil
-// }
+	raw, err := json.Marshal(userInfo)
+	if err != nil {
+		return err
+	}
+
+	if err := validateUserInfo(raw); err != nil {
+		return err
+	}
+
+	p.UserInfo = json.RawMessage(raw)
+
+	return nil
+}
 
 func (p *Pass) SetVoided(voided bool) error {
 	p.Voided = voided
@@ -407,6 +757,27 @@ func (p *Pass) ToJson() ([]byte, error) {
 	return json.Marshal(p)
 }
 
+func (p Pass) MarshalJSON() ([]byte, error) {
+	type pass Pass
+	out := pass(p)
+
+	if out.Barcode == nil {
+		out.Barcode = p.legacyBarcode()
+	}
+
+	if out.RelevantDate == nil {
+		out.RelevantDate = p.legacyRelevantDate()
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil || len(p.Extras) == 0 {
+		return data, err
+	}
+
+	return mergeExtras(data, p.Extras)
+}
+
+// Barcodes is a barcode shown on the pass.
 type Barcodes struct {
 	AltText         string        `json:"altText,omitempty"`
 	Format          BarcodeFormat `json:"format,omitempty"`
@@ -414,6 +785,7 @@ type Barcodes struct {
 	MessageEncoding string        `json:"messageEncoding,omitempty"`
 }
 
+// Beacons is a Bluetooth Low Energy beacon near which the pass is relevant.
 type Beacons struct {
 	Major         uint16 `json:"major,omitempty"`
 	Minor         uint16 `json:"minor,omitempty"`
@@ -421,6 +793,7 @@ type Beacons struct {
 	RelevantText  string `json:"relevantText,omitempty"`
 }
 
+// BoardingPass holds the fields of a boarding pass.
 type BoardingPass struct {
 	*PassFields
 	TransitType TransitType `json:"transitType,omitempty"`
@@ -430,6 +803,7 @@ func NewBoardingPass(transitType TransitType) *BoardingPass {
 	return &BoardingPass{PassFields: NewPassFields(), TransitType: transitType}
 }
 
+// Coupon holds the fields of a coupon.
 type Coupon struct {
 	*PassFields
 }
@@ -438,14 +812,17 @@ func NewCoupon() *Coupon {
 	return &Coupon{PassFields: NewPassFields()}
 }
 
+// EventTicket holds the fields of an event ticket.
 type EventTicket struct {
 	*PassFields
+	AdditionalInfoFields []PassFieldContent `json:"additionalInfoFields,omitempty"`
 }
 
 func NewEventTicket() *EventTicket {
 	return &EventTicket{PassFields: NewPassFields()}
 }
 
+// Generic holds the fields of a generic pass.
 type Generic struct {
 	*PassFields
 }
@@ -454,6 +831,7 @@ func NewGeneric() *Generic {
 	return &Generic{PassFields: NewPassFields()}
 }
 
+// PassFields groups the fields shown on the front and back of the pass.
 type PassFields struct {
 	AuxiliaryFields []PassFieldContent `json:"auxiliaryFields,omitempty"`
 	BackFields      []PassFieldContent `json:"backFields,omitempty"`
@@ -466,6 +844,7 @@ func NewPassFields() *PassFields {
 	return &PassFields{}
 }
 
+// PassFieldContent is a single labelled field of the pass.
 type PassFieldContent struct {
 	AttributedValue   string             `json:"attributedValue,omitempty"`
 	ChangeMessage     string             `json:"changeMessage,omitempty"`
@@ -479,9 +858,10 @@ type PassFieldContent struct {
 	NumberStyle       NumberStyle        `json:"numberStyle,omitempty"`
 	TextAlignment     TextAlignment      `json:"textAlignment,omitempty"`
 	TimeStyle         TimeStyle          `json:"timeStyle,omitempty"`
-	Value             string             `json:"value,omitempty"`
+	Value             *FieldValue        `json:"value,omitempty"`
 }
 
+// Locations is a place near which the pass is relevant.
 type Locations struct {
 	Altitude     float64 `json:"altitude,omitempty"`
 	Latitude     float64 `json:"latitude,omitempty"`
@@ -489,92 +869,144 @@ type Locations struct {
 	RelevantText string  `json:"relevantText,omitempty"`
 }
 
+// NFC holds the payload of a value-added services enabled pass.
 type NFC struct {
 	EncryptionPublicKey    string `json:"encryptionPublicKey,omitempty"`
 	Message                string `json:"message,omitempty"`
 	RequiresAuthentication bool   `json:"requiresAuthentication,omitempty"`
 }
 
+// SemanticTags is machine-readable metadata Wallet uses to offer suggestions and extra features.
 type SemanticTags struct {
-	AirlineCode                    string                `json:"airlineCode,omitempty"`
-	ArtistIDs                      string                `json:"artistIDs,omitempty"`
-	AwayTeamAbbreviation           string                `json:"awayTeamAbbreviation,omitempty"`
-	AwayTeamLocation               string                `json:"awayTeamLocation,omitempty"`
-	AwayTeamName                   string                `json:"awayTeamName,omitempty"`
-	Balance                        *CurrencyAmount       `json:"balance,omitempty"`
-	BoardingGroup                  string                `json:"boardingGroup,omitempty"`
-	BoardingSequenceNumber         string                `json:"boardingSequenceNumber,omitempty"`
-	CarNumber                      string                `json:"carNumber,omitempty"`
-	ConfirmationNumber             string                `json:"confirmationNumber,omitempty"`
-	CurrentArrivalDate             *time.Time            `json:"currentArrivalDate,omitempty"`
-	CurrentBoardingDate            *time.Time            `json:"currentBoardingDate,omitempty"`
-	CurrentDepartureDate           *time.Time            `json:"currentDepartureDate,omitempty"`
-	DepartureAirportCode           string                `json:"departureAirportCode,omitempty"`
-	DepartureAirportName           string                `json:"departureAirportName,omitempty"`
-	DepartureGate                  string                `json:"departureGate,omitempty"`
-	DepartureLocation              *Location             `json:"departureLocation,omitempty"`
-	DepartureLocationDescription   string                `json:"departureLocationDescription,omitempty"`
-	DeparturePlatform              string                `json:"departurePlatform,omitempty"`
-	DepartureStationName           string                `json:"departureStationName,omitempty"`
-	DepartureTerminal              string                `json:"departureTerminal,omitempty"`
-	DestinationAirportCode         string                `json:"destinationAirportCode,omitempty"`
-	DestinationAirportName         string                `json:"destinationAirportName,omitempty"`
-	DestinationGate                string                `json:"destinationGate,omitempty"`
-	DestinationLocation            *Location             `json:"destinationLocation,omitempty"`
-	DestinationLocationDescription string                `json:"destinationLocationDescription,omitempty"`
-	DestinationPlatform            string                `json:"destinationPlatform,omitempty"`
-	DestinationStationName         string                `json:"destinationStationName,omitempty"`
-	DestinationTerminal            string                `json:"destinationTerminal,omitempty"`
-	Duration                       int64                 `json:"duration,omitempty"`
-	EventEndDate                   *time.Time            `json:"eventEndDate,omitempty"`
-	EventName                      string                `json:"eventName,omitempty"`
-	EventStartDate                 *time.Time            `json:"eventStartDate,omitempty"`
-	EventType                      string                `json:"eventType,omitempty"`
-	FlightCode                     string                `json:"flightCode,omitempty"`
-	FlightNumber                   int64                 `json:"flightNumber,omitempty"`
-	Genre                          string                `json:"genre,omitempty"`
-	HomeTeamAbbreviation           string                `json:"homeTeamAbbreviation,omitempty"`
-	HomeTeamLocation               string                `json:"homeTeamLocation,omitempty"`
-	HomeTeamName                   string                `json:"homeTeamName,omitempty"`
-	LeagueAbbreviation             string                `json:"leagueAbbreviation,omitempty"`
-	LeagueName                     string                `json:"leagueName,omitempty"`
-	MembershipProgramName          string                `json:"membershipProgramName,omitempty"`
-	MembershipProgramNumber        string                `json:"membershipProgramNumber,omitempty"`
-	OriginalArrivalDate            *time.Time            `json:"originalArrivalDate,omitempty"`
-	OriginalBoardingDate           *time.Time            `json:"originalBoardingDate,omitempty"`
-	OriginalDepartureDate          *time.Time            `json:"originalDepartureDate,omitempty"`
-	PassengerName                  *PersonNameComponents `json:"passengerName,omitempty"`
-	PerformerNames                 []string              `json:"performerNames,omitempty"`
-	PriorityStatus                 string                `json:"priorityStatus,omitempty"`
-	Seats                          *Seat                 `json:"seats,omitempty"`
-	SecurityScreening              string                `json:"securityScreening,omitempty"`
-	SilenceRequested               bool                  `json:"silenceRequested,omitempty"`
-	SportName                      string                `json:"sportName,omitempty"`
-	TotalPrice                     *CurrencyAmount       `json:"totalPrice,omitempty"`
-	TransitProvider                string                `json:"transitProvider,omitempty"`
-	TransitStatus                  string                `json:"transitStatus,omitempty"`
-	TransitStatusReason            string                `json:"transitStatusReason,omitempty"`
-	VehicleName                    string                `json:"vehicleName,omitempty"`
-	VehicleNumber                  string                `json:"vehicleNumber,omitempty"`
-	VehicleType                    string                `json:"vehicleType,omitempty"`
-	VenueEntrance                  string                `json:"venueEntrance,omitempty"`
-	VenueLocation                  *Location             `json:"venueLocation,omitempty"`
-	VenueName                      string                `json:"venueName,omitempty"`
-	VenuePhoneNumber               string                `json:"venuePhoneNumber,omitempty"`
-	VenueRoom                      string                `json:"venueRoom,omitempty"`
-	WifiAccess                     *WifiNetwork          `json:"wifiAccess,omitempty"`
+	AdditionalTicketAttributes                    string                   `json:"additionalTicketAttributes,omitempty"`
+	AdmissionLevel                                string                   `json:"admissionLevel,omitempty"`
+	AdmissionLevelAbbreviation                    string                   `json:"admissionLevelAbbreviation,omitempty"`
+	AirlineCode                                   string                   `json:"airlineCode,omitempty"`
+	AlbumIDs                                      []string                 `json:"albumIDs,omitempty"`
+	ArtistIDs                                     []string                 `json:"artistIDs,omitempty"`
+	AttendeeName                                  string                   `json:"attendeeName,omitempty"`
+	AwayTeamAbbreviation                          string                   `json:"awayTeamAbbreviation,omitempty"`
+	AwayTeamLocation                              string                   `json:"awayTeamLocation,omitempty"`
+	AwayTeamName                                  string                   `json:"awayTeamName,omitempty"`
+	Balance                                       *CurrencyAmount          `json:"balance,omitempty"`
+	BoardingGroup                                 string                   `json:"boardingGroup,omitempty"`
+	BoardingSequenceNumber                        string                   `json:"boardingSequenceNumber,omitempty"`
+	BoardingZone                                  string                   `json:"boardingZone,omitempty"`
+	CarNumber                                     string                   `json:"carNumber,omitempty"`
+	ConfirmationNumber                            string                   `json:"confirmationNumber,omitempty"`
+	CurrentArrivalDate                            *Date                    `json:"currentArrivalDate,omitempty"`
+	CurrentBoardingDate                           *Date                    `json:"currentBoardingDate,omitempty"`
+	CurrentDepartureDate                          *Date                    `json:"currentDepartureDate,omitempty"`
+	DepartureAirportCode                          string                   `json:"departureAirportCode,omitempty"`
+	DepartureAirportName                          string                   `json:"departureAirportName,omitempty"`
+	DepartureCityName                             string                   `json:"departureCityName,omitempty"`
+	DepartureGate                                 string                   `json:"departureGate,omitempty"`
+	DepartureLocation                             *Location                `json:"departureLocation,omitempty"`
+	DepartureLocationDescription                  string                   `json:"departureLocationDescription,omitempty"`
+	DepartureLocationSecurityPrograms             []TransitSecurityProgram `json:"departureLocationSecurityPrograms,omitempty"`
+	DepartureLocationTimeZone                     string                   `json:"departureLocationTimeZone,omitempty"`
+	DeparturePlatform                             string                   `json:"departurePlatform,omitempty"`
+	DepartureStationName                          string                   `json:"departureStationName,omitempty"`
+	DepartureTerminal                             string                   `json:"departureTerminal,omitempty"`
+	DestinationAirportCode                        string                   `json:"destinationAirportCode,omitempty"`
+	DestinationAirportName                        string                   `json:"destinationAirportName,omitempty"`
+	DestinationCityName                           string                   `json:"destinationCityName,omitempty"`
+	DestinationGate                               string                   `json:"destinationGate,omitempty"`
+	DestinationLocation                           *Location                `json:"destinationLocation,omitempty"`
+	DestinationLocationDescription                string                   `json:"destinationLocationDescription,omitempty"`
+	DestinationLocationSecurityPrograms           []TransitSecurityProgram `json:"destinationLocationSecurityPrograms,omitempty"`
+	DestinationLocationTimeZone                   string                   `json:"destinationLocationTimeZone,omitempty"`
+	DestinationPlatform                           string                   `json:"destinationPlatform,omitempty"`
+	DestinationStationName                        string                   `json:"destinationStationName,omitempty"`
+	DestinationTerminal                           string                   `json:"destinationTerminal,omitempty"`
+	Duration                                      int64                    `json:"duration,omitempty"`
+	EntranceDescription                           string                   `json:"entranceDescription,omitempty"`
+	EventEndDate                                  *Date                    `json:"eventEndDate,omitempty"`
+	EventLiveMessage                              string                   `json:"eventLiveMessage,omitempty"`
+	EventName                                     string                   `json:"eventName,omitempty"`
+	EventStartDate                                *Date                    `json:"eventStartDate,omitempty"`
+	EventStartDateInfo                            *EventDateInfo           `json:"eventStartDateInfo,omitempty"`
+	EventType                                     EventType                `json:"eventType,omitempty"`
+	FlightCode                                    string                   `json:"flightCode,omitempty"`
+	FlightNumber                                  int64                    `json:"flightNumber,omitempty"`
+	Genre                                         string                   `json:"genre,omitempty"`
+	HomeTeamAbbreviation                          string                   `json:"homeTeamAbbreviation,omitempty"`
+	HomeTeamLocation                              string                   `json:"homeTeamLocation,omitempty"`
+	HomeTeamName                                  string                   `json:"homeTeamName,omitempty"`
+	InternationalDocumentsAreVerified             bool                     `json:"internationalDocumentsAreVerified,omitempty"`
+	InternationalDocumentsVerifiedDeclarationName string                   `json:"internationalDocumentsVerifiedDeclarationName,omitempty"`
+	LeagueAbbreviation                            string                   `json:"leagueAbbreviation,omitempty"`
+	LeagueName                                    string                   `json:"leagueName,omitempty"`
+	LoungePlaceholder                             bool                     `json:"loungePlaceholder,omitempty"`
+	MembershipProgramName                         string                   `json:"membershipProgramName,omitempty"`
+	MembershipProgramNumber                       string                   `json:"membershipProgramNumber,omitempty"`
+	MembershipProgramStatus                       string                   `json:"membershipProgramStatus,omitempty"`
+	OriginalArrivalDate                           *Date                    `json:"originalArrivalDate,omitempty"`
+	OriginalBoardingDate                          *Date                    `json:"originalBoardingDate,omitempty"`
+	OriginalDepartureDate                         *Date                    `json:"originalDepartureDate,omitempty"`
+	PassengerAirlineSSRs                          []string                 `json:"passengerAirlineSSRs,omitempty"`
+	PassengerCapabilities                         []PassengerCapability    `json:"passengerCapabilities,omitempty"`
+	PassengerEligibleSecurityPrograms             []TransitSecurityProgram `json:"passengerEligibleSecurityPrograms,omitempty"`
+	PassengerInformationSSRs                      []string                 `json:"passengerInformationSSRs,omitempty"`
+	PassengerName                                 *PersonNameComponents    `json:"passengerName,omitempty"`
+	PassengerServiceSSRs                          []string                 `json:"passengerServiceSSRs,omitempty"`
+	PerformerNames                                []string                 `json:"performerNames,omitempty"`
+	PlaylistIDs                                   []string                 `json:"playlistIDs,omitempty"`
+	PriorityStatus                                string                   `json:"priorityStatus,omitempty"`
+	Seats                                         []Seat                   `json:"seats,omitempty"`
+	SecurityScreening                             string                   `json:"securityScreening,omitempty"`
+	SilenceRequested                              bool                     `json:"silenceRequested,omitempty"`
+	SportName                                     string                   `json:"sportName,omitempty"`
+	TailgatingAllowed                             bool                     `json:"tailgatingAllowed,omitempty"`
+	TicketFareClass                               string                   `json:"ticketFareClass,omitempty"`
+	TotalPrice                                    *CurrencyAmount          `json:"totalPrice,omitempty"`
+	TransitProvider                               string                   `json:"transitProvider,omitempty"`
+	TransitStatus                                 string                   `json:"transitStatus,omitempty"`
+	TransitStatusReason                           string                   `json:"transitStatusReason,omitempty"`
+	VehicleName                                   string                   `json:"vehicleName,omitempty"`
+	VehicleNumber                                 string                   `json:"vehicleNumber,omitempty"`
+	VehicleType                                   string                   `json:"vehicleType,omitempty"`
+	VenueBoxOfficeOpenDate                        *Date                    `json:"venueBoxOfficeOpenDate,omitempty"`
+	VenueCloseDate                                *Date                    `json:"venueCloseDate,omitempty"`
+	VenueDoorsOpenDate                            *Date                    `json:"venueDoorsOpenDate,omitempty"`
+	VenueEntrance                                 string                   `json:"venueEntrance,omitempty"`
+	VenueEntranceDoor                             string                   `json:"venueEntranceDoor,omitempty"`
+	VenueEntranceGate                             string                   `json:"venueEntranceGate,omitempty"`
+	VenueEntrancePortal                           string                   `json:"venueEntrancePortal,omitempty"`
+	VenueFanZoneOpenDate                          *Date                    `json:"venueFanZoneOpenDate,omitempty"`
+	VenueGatesOpenDate                            *Date                    `json:"venueGatesOpenDate,omitempty"`
+	VenueLocation                                 *Location                `json:"venueLocation,omitempty"`
+	VenueName                                     string                   `json:"venueName,omitempty"`
+	VenueOpenDate                                 *Date                    `json:"venueOpenDate,omitempty"`
+	VenueParkingLotsOpenDate                      *Date                    `json:"venueParkingLotsOpenDate,omitempty"`
+	VenuePhoneNumber                              string                   `json:"venuePhoneNumber,omitempty"`
+	VenueRegionName                               string                   `json:"venueRegionName,omitempty"`
+	VenueRoom                                     string                   `json:"venueRoom,omitempty"`
+	WifiAccess                                    []WifiNetwork            `json:"wifiAccess,omitempty"`
 }
 
+// CurrencyAmount is an amount of money in an ISO 4217 currency.
 type CurrencyAmount struct {
 	Amount       string `json:"amount,omitempty"`
 	CurrencyCode string `json:"currencyCode,omitempty"`
 }
 
+// EventDateInfo is the start date of an event with hints on how precisely it is known.
+type EventDateInfo struct {
+	Date                 *Date  `json:"date,omitempty"`
+	IgnoreTimeComponents bool   `json:"ignoreTimeComponents,omitempty"`
+	TimeZone             string `json:"timeZone,omitempty"`
+	Unannounced          bool   `json:"unannounced,omitempty"`
+	Undetermined         bool   `json:"undetermined,omitempty"`
+}
+
+// Location is a geographic coordinate.
 type Location struct {
 	Latitude  float64 `json:"latitude,omitempty"`
 	Longitude float64 `json:"longitude,omitempty"`
 }
 
+// PersonNameComponents is a name split into its parts.
 type PersonNameComponents struct {
 	FamilyName             string `json:"familyName,omitempty"`
 	GivenName              string `json:"givenName,omitempty"`
@@ -585,20 +1017,26 @@ type PersonNameComponents struct {
 	PhoneticRepresentation string `json:"phoneticRepresentation,omitempty"`
 }
 
+// Seat describes a seat of a boarding pass or event ticket.
 type Seat struct {
-	SeatDescription string `json:"seatDescription,omitempty"`
-	SeatIdentifier  string `json:"seatIdentifier,omitempty"`
-	SeatNumber      string `json:"seatNumber,omitempty"`
-	SeatRow         string `json:"seatRow,omitempty"`
-	SeatSection     string `json:"seatSection,omitempty"`
-	SeatType        string `json:"seatType,omitempty"`
+	SeatAisle        string `json:"seatAisle,omitempty"`
+	SeatDescription  string `json:"seatDescription,omitempty"`
+	SeatIdentifier   string `json:"seatIdentifier,omitempty"`
+	SeatLevel        string `json:"seatLevel,omitempty"`
+	SeatNumber       string `json:"seatNumber,omitempty"`
+	SeatRow          string `json:"seatRow,omitempty"`
+	SeatSection      string `json:"seatSection,omitempty"`
+	SeatSectionColor *Color `json:"seatSectionColor,omitempty"`
+	SeatType         string `json:"seatType,omitempty"`
 }
 
+// WifiNetwork is a Wi-Fi network the pass gives access to.
 type WifiNetwork struct {
 	Password string `json:"password,omitempty"`
 	Ssid     string `json:"ssid,omitempty"`
 }
 
+// StoreCard holds the fields of a store card.
 type StoreCard struct {
 	*PassFields
 }
@@ -607,6 +1045,7 @@ func NewStoreCard() *StoreCard {
 	return &StoreCard{PassFields: NewPassFields()}
 }
 
+// Personalize is the personalization.json of a reward enrollment pass.
 type Personalize struct {
 	Description                   string                     `json:"description,omitempty"`
 	RequiredPersonalizationFields []PassPersonalizationField `json:"requiredPersonalizationFields,omitempty"`
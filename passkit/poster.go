@@ -0,0 +1,86 @@
+package passkit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+func isValidStyleScheme(s StyleScheme) bool {
+	switch s {
+	case StyleSchemeEventTicket, StyleSchemePosterEventTicket:
+		return true
+	}
+
+	return false
+}
+
+// PrefersPosterLayout reports whether the pass asks for the poster event
+// ticket layout introduced in iOS 18.
+func (p *Pass) PrefersPosterLayout() bool {
+	return slices.Contains(p.PreferredStyleSchemes, StyleSchemePosterEventTicket)
+}
+
+func (p *Pass) validateStyleSchemes() error {
+	var errs []error
+
+	for _, s := range p.PreferredStyleSchemes {
+		if !isValidStyleScheme(s) {
+			errs = append(errs, fmt.Errorf("Unknown style scheme %q", s))
+		}
+	}
+
+	if p.PrefersPosterLayout() {
+		if p.EventTicket == nil {
+			errs = append(errs, errors.New("Poster layout is only supported on event tickets"))
+		}
+
+		// Older systems and passes that miss a requirement of the poster
+		// layout show the first scheme they support, so the classic layout
+		// has to follow the poster one.
+		poster := slices.Index(p.PreferredStyleSchemes, StyleSchemePosterEventTicket)
+		if !slices.Contains(p.PreferredStyleSchemes[poster+1:], StyleSchemeEventTicket) {
+			errs = append(errs, errors.New("Preferred style schemes must fall back to eventTicket after posterEventTicket"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// PosterLayout reports why Wallet would not show the pass in the poster event
+// ticket layout, given the roles of the images in its bundle. It returns nil
+// if the poster layout engages.
+func (p *Pass) PosterLayout(images []ImageRole) error {
+	var errs []error
+
+	if !p.PrefersPosterLayout() {
+		errs = append(errs, errors.New("Preferred style schemes do not include posterEventTicket"))
+	}
+
+	if p.EventTicket == nil {
+		errs = append(errs, errors.New("Poster layout is only supported on event tickets"))
+	}
+
+	s := p.Semantics
+	if s == nil {
+		s = &SemanticTags{}
+	}
+
+	if s.EventName == "" {
+		errs = append(errs, errors.New("Poster layout requires the eventName semantic tag"))
+	}
+
+	if s.VenueName == "" {
+		errs = append(errs, errors.New("Poster layout requires the venueName semantic tag"))
+	}
+
+	if s.EventStartDate == nil && s.EventStartDateInfo == nil {
+		errs = append(errs, errors.New("Poster layout requires the eventStartDate or eventStartDateInfo semantic tag"))
+	}
+
+	if !slices.Contains(images, ImageRoleArtwork) {
+		errs = append(errs, errors.New("Poster layout requires an artwork image"))
+	}
+
+	return errors.Join(errs...)
+}
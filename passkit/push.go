@@ -0,0 +1,59 @@
+package passkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APNsServer is the push server for passes; Wallet has no sandbox for them.
+const APNsServer = "https://api.push.apple.com"
+
+// ErrPushTokenInactive is returned by Push when APNs reports that the device
+// no longer accepts pushes for the token; its registrations can be dropped.
+var ErrPushTokenInactive = errors.New("Push token is no longer active")
+
+// Push tells the device behind pushToken to fetch the passes of
+// passTypeIdentifier that changed. client must present the Pass Type ID
+// certificate over HTTP/2; server is normally APNsServer.
+func Push(ctx context.Context, client *http.Client, server, passTypeIdentifier, pushToken string) error {
+	if passTypeIdentifier == "" {
+		return errors.New("Pass type identifier can not be empty")
+	}
+
+	if pushToken == "" {
+		return errors.New("Push token can not be empty")
+	}
+
+	url := strings.TrimSuffix(server, "/") + "/3/device/" + pushToken
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader("{}"))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("apns-topic", passTypeIdentifier)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+
+	if resp.StatusCode == http.StatusGone {
+		return ErrPushTokenInactive
+	}
+
+	return fmt.Errorf("APNs rejected the push with status %d: %s", resp.StatusCode, body.Reason)
+}
@@ -0,0 +1,116 @@
+package passkit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// RelevantDate is an entry of Pass.RelevantDates: either a single Date or an
+// interval from StartDate to EndDate.
+type RelevantDate struct {
+	Date      *Date `json:"date,omitempty"`
+	EndDate   *Date `json:"endDate,omitempty"`
+	StartDate *Date `json:"startDate,omitempty"`
+}
+
+func NewRelevantDate(t time.Time) RelevantDate {
+	return RelevantDate{Date: NewDate(t)}
+}
+
+func NewRelevantInterval(start, end time.Time) RelevantDate {
+	return RelevantDate{StartDate: NewDate(start), EndDate: NewDate(end)}
+}
+
+// Start returns the date, or the start of the interval.
+func (r RelevantDate) Start() time.Time {
+	if r.Date != nil {
+		return r.Date.Time
+	}
+
+	if r.StartDate != nil {
+		return r.StartDate.Time
+	}
+
+	return time.Time{}
+}
+
+// End returns the date, or the end of the interval.
+func (r RelevantDate) End() time.Time {
+	if r.Date != nil {
+		return r.Date.Time
+	}
+
+	if r.EndDate != nil {
+		return r.EndDate.Time
+	}
+
+	return time.Time{}
+}
+
+func (r RelevantDate) Validate() error {
+	switch {
+	case r.Date != nil && (r.StartDate != nil || r.EndDate != nil):
+		return errors.New("Relevant date can not have both a date and an interval")
+	case r.Date != nil:
+		return nil
+	case r.StartDate == nil || r.EndDate == nil:
+		return errors.New("Relevant date needs a date or both a start and an end date")
+	case !r.EndDate.After(r.StartDate.Time):
+		return errors.New("Relevant date must end after it starts")
+	}
+
+	return nil
+}
+
+// validateRelevantDates checks each entry and that the entries are in
+// chronological order without overlapping or repeating.
+func (p *Pass) validateRelevantDates() error {
+	var errs []error
+
+	// last is the previous valid entry and latest the one that ends last so
+	// far, as an interval can outlast the entries that follow it.
+	last, latest := -1, -1
+	for i, r := range p.RelevantDates {
+		if err := r.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Relevant date %d: %w", i, err))
+			continue
+		}
+
+		if last >= 0 {
+			prev, end := p.RelevantDates[last], p.RelevantDates[latest].End()
+			switch {
+			case r.Start().Before(prev.Start()):
+				errs = append(errs, fmt.Errorf("Relevant date %d: starts before relevant date %d", i, last))
+			case r.Start().Equal(prev.Start()) && r.End().Equal(prev.End()):
+				errs = append(errs, fmt.Errorf("Relevant date %d: duplicates relevant date %d", i, last))
+			case r.Start().Before(end):
+				errs = append(errs, fmt.Errorf("Relevant date %d: overlaps relevant date %d", i, latest))
+			case r.Start().Equal(prev.Start()):
+				errs = append(errs, fmt.Errorf("Relevant date %d: overlaps relevant date %d", i, last))
+			}
+		}
+
+		last = i
+		if latest < 0 || r.End().After(p.RelevantDates[latest].End()) {
+			latest = i
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// legacyRelevantDate returns the earliest start of the relevant dates, which
+// devices that predate relevantDates show instead.
+func (p *Pass) legacyRelevantDate() *Date {
+	if len(p.RelevantDates) == 0 {
+		return nil
+	}
+
+	first := slices.MinFunc(p.RelevantDates, func(a, b RelevantDate) int {
+		return a.Start().Compare(b.Start())
+	})
+
+	return NewDate(first.Start())
+}
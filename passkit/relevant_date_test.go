@@ -0,0 +1,110 @@
+package passkit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateRelevantDates(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2024, 6, 20, hour, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		dates []RelevantDate
+		want  string
+	}{
+		{
+			name:  "in order",
+			dates: []RelevantDate{NewRelevantDate(at(8)), NewRelevantInterval(at(9), at(11)), NewRelevantInterval(at(11), at(12))},
+		},
+		{
+			name:  "out of order",
+			dates: []RelevantDate{NewRelevantDate(at(9)), NewRelevantDate(at(8))},
+			want:  "Relevant date 1: starts before relevant date 0",
+		},
+		{
+			name:  "duplicate date",
+			dates: []RelevantDate{NewRelevantDate(at(9)), NewRelevantDate(at(9))},
+			want:  "Relevant date 1: duplicates relevant date 0",
+		},
+		{
+			name:  "duplicate interval",
+			dates: []RelevantDate{NewRelevantInterval(at(9), at(10)), NewRelevantInterval(at(9), at(10))},
+			want:  "Relevant date 1: duplicates relevant date 0",
+		},
+		{
+			name:  "overlapping previous",
+			dates: []RelevantDate{NewRelevantInterval(at(9), at(11)), NewRelevantInterval(at(10), at(12))},
+			want:  "Relevant date 1: overlaps relevant date 0",
+		},
+		{
+			name:  "date at start of interval",
+			dates: []RelevantDate{NewRelevantDate(at(9)), NewRelevantInterval(at(9), at(10))},
+			want:  "Relevant date 1: overlaps relevant date 0",
+		},
+		{
+			name: "inside an earlier interval",
+			dates: []RelevantDate{
+				NewRelevantInterval(at(8), at(18)),
+				NewRelevantInterval(at(9), at(10)),
+				NewRelevantDate(at(12)),
+			},
+			want: "Relevant date 1: overlaps relevant date 0\nRelevant date 2: overlaps relevant date 0",
+		},
+		{
+			name:  "skips invalid entries",
+			dates: []RelevantDate{NewRelevantDate(at(9)), {}, NewRelevantDate(at(9))},
+			want:  "Relevant date 1: Relevant date needs a date or both a start and an end date\nRelevant date 2: duplicates relevant date 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pass{RelevantDates: tt.dates}
+
+			err := p.validateRelevantDates()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || strings.TrimSpace(err.Error()) != tt.want {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRelevantDatesReplacesLegacyDate(t *testing.T) {
+	p, err := DecodePass(decodeTestJSON(`"eventTicket": {}, "relevantDate": "2024-06-20T19:00:00-07:00"`), DecodeLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+	if err := p.SetRelevantDates([]RelevantDate{NewRelevantInterval(start, start.Add(time.Hour))}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := p.ToJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodePass(data, DecodeLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.RelevantDate == nil || !decoded.RelevantDate.Equal(start) {
+		t.Errorf("relevantDate = %v, want %v", decoded.RelevantDate, start)
+	}
+	if len(decoded.RelevantDates) != 1 || !decoded.RelevantDates[0].Start().Equal(start) {
+		t.Errorf("relevantDates = %v", decoded.RelevantDates)
+	}
+}
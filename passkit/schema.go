@@ -0,0 +1,11 @@
+package passkit
+
+import _ "embed"
+
+//go:generate go run ./internal/schemagen -o pass.schema.json
+
+// JSONSchema is the JSON Schema (draft 2020-12) of pass.json, generated from
+// the types of this package.
+//
+//go:embed pass.schema.json
+var JSONSchema []byte
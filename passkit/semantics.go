@@ -0,0 +1,326 @@
+package passkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	boardingPassOnly = []PassStyle{PassStyleBoardingPass}
+	eventTicketOnly  = []PassStyle{PassStyleEventTicket}
+)
+
+// semanticTagStyles lists the pass styles each semantic tag applies to.
+// Tags that are not listed apply to every style.
+var semanticTagStyles = map[string][]PassStyle{
+	"additionalTicketAttributes":          eventTicketOnly,
+	"admissionLevel":                      eventTicketOnly,
+	"admissionLevelAbbreviation":          eventTicketOnly,
+	"airlineCode":                         boardingPassOnly,
+	"albumIDs":                            eventTicketOnly,
+	"artistIDs":                           eventTicketOnly,
+	"attendeeName":                        eventTicketOnly,
+	"awayTeamAbbreviation":                eventTicketOnly,
+	"awayTeamLocation":                    eventTicketOnly,
+	"awayTeamName":                        eventTicketOnly,
+	"balance":                             {PassStyleStoreCard},
+	"boardingGroup":                       boardingPassOnly,
+	"boardingSequenceNumber":              boardingPassOnly,
+	"boardingZone":                        boardingPassOnly,
+	"carNumber":                           boardingPassOnly,
+	"currentArrivalDate":                  boardingPassOnly,
+	"currentBoardingDate":                 boardingPassOnly,
+	"currentDepartureDate":                boardingPassOnly,
+	"departureAirportCode":                boardingPassOnly,
+	"departureAirportName":                boardingPassOnly,
+	"departureCityName":                   boardingPassOnly,
+	"departureGate":                       boardingPassOnly,
+	"departureLocation":                   boardingPassOnly,
+	"departureLocationDescription":        boardingPassOnly,
+	"departureLocationSecurityPrograms":   boardingPassOnly,
+	"departureLocationTimeZone":           boardingPassOnly,
+	"departurePlatform":                   boardingPassOnly,
+	"departureStationName":                boardingPassOnly,
+	"departureTerminal":                   boardingPassOnly,
+	"destinationAirportCode":              boardingPassOnly,
+	"destinationAirportName":              boardingPassOnly,
+	"destinationCityName":                 boardingPassOnly,
+	"destinationGate":                     boardingPassOnly,
+	"destinationLocation":                 boardingPassOnly,
+	"destinationLocationDescription":      boardingPassOnly,
+	"destinationLocationSecurityPrograms": boardingPassOnly,
+	"destinationLocationTimeZone":         boardingPassOnly,
+	"destinationPlatform":                 boardingPassOnly,
+	"destinationStationName":              boardingPassOnly,
+	"destinationTerminal":                 boardingPassOnly,
+	"entranceDescription":                 eventTicketOnly,
+	"eventEndDate":                        eventTicketOnly,
+	"eventLiveMessage":                    eventTicketOnly,
+	"eventName":                           eventTicketOnly,
+	"eventStartDate":                      eventTicketOnly,
+	"eventStartDateInfo":                  eventTicketOnly,
+	"eventType":                           eventTicketOnly,
+	"flightCode":                          boardingPassOnly,
+	"flightNumber":                        boardingPassOnly,
+	"genre":                               eventTicketOnly,
+	"homeTeamAbbreviation":                eventTicketOnly,
+	"homeTeamLocation":                    eventTicketOnly,
+	"homeTeamName":                        eventTicketOnly,
+	"internationalDocumentsAreVerified":   boardingPassOnly,
+	"internationalDocumentsVerifiedDeclarationName": boardingPassOnly,
+	"leagueAbbreviation":                            eventTicketOnly,
+	"leagueName":                                    eventTicketOnly,
+	"loungePlaceholder":                             boardingPassOnly,
+	"originalArrivalDate":                           boardingPassOnly,
+	"originalBoardingDate":                          boardingPassOnly,
+	"originalDepartureDate":                         boardingPassOnly,
+	"passengerAirlineSSRs":                          boardingPassOnly,
+	"passengerCapabilities":                         boardingPassOnly,
+	"passengerEligibleSecurityPrograms":             boardingPassOnly,
+	"passengerInformationSSRs":                      boardingPassOnly,
+	"passengerName":                                 boardingPassOnly,
+	"passengerServiceSSRs":                          boardingPassOnly,
+	"performerNames":                                eventTicketOnly,
+	"playlistIDs":                                   eventTicketOnly,
+	"priorityStatus":                                boardingPassOnly,
+	"seats":                                         {PassStyleBoardingPass, PassStyleEventTicket},
+	"securityScreening":                             boardingPassOnly,
+	"sportName":                                     eventTicketOnly,
+	"tailgatingAllowed":                             eventTicketOnly,
+	"ticketFareClass":                               boardingPassOnly,
+	"transitProvider":                               boardingPassOnly,
+	"transitStatus":                                 boardingPassOnly,
+	"transitStatusReason":                           boardingPassOnly,
+	"vehicleName":                                   boardingPassOnly,
+	"vehicleNumber":                                 boardingPassOnly,
+	"vehicleType":                                   boardingPassOnly,
+	"venueBoxOfficeOpenDate":                        eventTicketOnly,
+	"venueCloseDate":                                eventTicketOnly,
+	"venueDoorsOpenDate":                            eventTicketOnly,
+	"venueEntrance":                                 eventTicketOnly,
+	"venueEntranceDoor":                             eventTicketOnly,
+	"venueEntranceGate":                             eventTicketOnly,
+	"venueEntrancePortal":                           eventTicketOnly,
+	"venueFanZoneOpenDate":                          eventTicketOnly,
+	"venueGatesOpenDate":                            eventTicketOnly,
+	"venueLocation":                                 eventTicketOnly,
+	"venueName":                                     eventTicketOnly,
+	"venueOpenDate":                                 eventTicketOnly,
+	"venueParkingLotsOpenDate":                      eventTicketOnly,
+	"venuePhoneNumber":                              eventTicketOnly,
+	"venueRegionName":                               eventTicketOnly,
+	"venueRoom":                                     eventTicketOnly,
+}
+
+// UnmarshalJSON accepts artistIDs, seats and wifiAccess both as arrays, as
+// Apple defines them, and as the single values older versions of this
+// package wrote.
+func (s *SemanticTags) UnmarshalJSON(data []byte) error {
+	type semanticTags SemanticTags
+	aux := struct {
+		*semanticTags
+		ArtistIDs  json.RawMessage `json:"artistIDs,omitempty"`
+		Seats      json.RawMessage `json:"seats,omitempty"`
+		WifiAccess json.RawMessage `json:"wifiAccess,omitempty"`
+	}{semanticTags: (*semanticTags)(s)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if err := decodeOneOrMany(aux.ArtistIDs, &s.ArtistIDs); err != nil {
+		return err
+	}
+
+	if err := decodeOneOrMany(aux.Seats, &s.Seats); err != nil {
+		return err
+	}
+
+	return decodeOneOrMany(aux.WifiAccess, &s.WifiAccess)
+}
+
+func decodeOneOrMany[T any](raw json.RawMessage, dst *[]T) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		*dst = nil
+		return nil
+	}
+
+	if raw[0] == '[' {
+		return json.Unmarshal(raw, dst)
+	}
+
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return err
+	}
+
+	*dst = []T{v}
+
+	return nil
+}
+
+var (
+	airOnly    = []TransitType{TransitTypeAir}
+	groundOnly = []TransitType{TransitTypeBoat, TransitTypeBus, TransitTypeGeneric, TransitTypeTrain}
+)
+
+// semanticTagTransitTypes lists the boarding pass transit types each semantic
+// tag applies to. Tags that are not listed apply to every transit type.
+var semanticTagTransitTypes = map[string][]TransitType{
+	"airlineCode":                                   airOnly,
+	"carNumber":                                     groundOnly,
+	"departureAirportCode":                          airOnly,
+	"departureAirportName":                          airOnly,
+	"departurePlatform":                             groundOnly,
+	"departureStationName":                          groundOnly,
+	"destinationAirportCode":                        airOnly,
+	"destinationAirportName":                        airOnly,
+	"destinationPlatform":                           groundOnly,
+	"destinationStationName":                        groundOnly,
+	"flightCode":                                    airOnly,
+	"flightNumber":                                  airOnly,
+	"internationalDocumentsAreVerified":             airOnly,
+	"internationalDocumentsVerifiedDeclarationName": airOnly,
+	"loungePlaceholder":                             airOnly,
+	"passengerAirlineSSRs":                          airOnly,
+	"passengerInformationSSRs":                      airOnly,
+	"passengerServiceSSRs":                          airOnly,
+}
+
+var (
+	iataAirportPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	iataAirlinePattern = regexp.MustCompile(`^[A-Z0-9]{2}$`)
+	flightCodePattern  = regexp.MustCompile(`^[A-Z0-9]{2}[0-9]{1,4}[A-Z]?$`)
+)
+
+// setTags returns the JSON keys of all semantic tags that are set.
+func (s *SemanticTags) setTags() []string {
+	var tags []string
+
+	v := reflect.ValueOf(s).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		tags = append(tags, name)
+	}
+
+	return tags
+}
+
+func isValidEventType(t EventType) bool {
+	switch t {
+	case EventTypeGeneric, EventTypeLivePerformance, EventTypeMovie, EventTypeSports,
+		EventTypeConference, EventTypeConvention, EventTypeWorkshop, EventTypeSocialGathering:
+		return true
+	}
+
+	return false
+}
+
+func isValidPassengerCapability(c PassengerCapability) bool {
+	switch c {
+	case PassengerCapabilityPreboarding, PassengerCapabilityPriorityBoarding,
+		PassengerCapabilityCarryon, PassengerCapabilityPersonalItem:
+		return true
+	}
+
+	return false
+}
+
+func isValidTransitSecurityProgram(p TransitSecurityProgram) bool {
+	switch p {
+	case TransitSecurityProgramTSAPreCheck, TransitSecurityProgramTSAPreCheckTouchlessID,
+		TransitSecurityProgramOSS, TransitSecurityProgramITI, TransitSecurityProgramITD,
+		TransitSecurityProgramGlobalEntry, TransitSecurityProgramCLEAR:
+		return true
+	}
+
+	return false
+}
+
+// Validate checks that every tag that is set applies to the given pass style
+// and that enumerated tags hold values Apple defines.
+func (s *SemanticTags) Validate(style PassStyle) error {
+	var errs []error
+
+	for _, tag := range s.setTags() {
+		styles, ok := semanticTagStyles[tag]
+		if ok && style != "" && !slices.Contains(styles, style) {
+			errs = append(errs, fmt.Errorf("Semantic tag %q does not apply to %s passes", tag, style))
+		}
+	}
+
+	if s.EventType != "" && !isValidEventType(s.EventType) {
+		errs = append(errs, fmt.Errorf("Unknown event type %q", s.EventType))
+	}
+
+	for _, c := range s.PassengerCapabilities {
+		if !isValidPassengerCapability(c) {
+			errs = append(errs, fmt.Errorf("Unknown passenger capability %q", c))
+		}
+	}
+
+	programs := slices.Concat(s.PassengerEligibleSecurityPrograms, s.DepartureLocationSecurityPrograms, s.DestinationLocationSecurityPrograms)
+	for _, p := range programs {
+		if !isValidTransitSecurityProgram(p) {
+			errs = append(errs, fmt.Errorf("Unknown transit security program %q", p))
+		}
+	}
+
+	if s.AirlineCode != "" && !iataAirlinePattern.MatchString(s.AirlineCode) {
+		errs = append(errs, fmt.Errorf("Airline code %q is not an IATA airline designator", s.AirlineCode))
+	}
+
+	if s.FlightCode != "" && !flightCodePattern.MatchString(s.FlightCode) {
+		errs = append(errs, fmt.Errorf("Flight code %q is not an IATA flight code", s.FlightCode))
+	}
+
+	if s.DepartureAirportCode != "" && !iataAirportPattern.MatchString(s.DepartureAirportCode) {
+		errs = append(errs, fmt.Errorf("Departure airport code %q is not an IATA airport code", s.DepartureAirportCode))
+	}
+
+	if s.DestinationAirportCode != "" && !iataAirportPattern.MatchString(s.DestinationAirportCode) {
+		errs = append(errs, fmt.Errorf("Destination airport code %q is not an IATA airport code", s.DestinationAirportCode))
+	}
+
+	if s.Balance != nil {
+		if err := s.Balance.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Balance: %w", err))
+		}
+	}
+
+	if s.TotalPrice != nil {
+		if err := s.TotalPrice.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Total price: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateTransitType checks that every tag that is set applies to a boarding
+// pass of the given transit type, so that flight tags are not used on a train
+// ticket and station tags not on a flight.
+func (s *SemanticTags) ValidateTransitType(transitType TransitType) error {
+	var errs []error
+
+	for _, tag := range s.setTags() {
+		types, ok := semanticTagTransitTypes[tag]
+		if ok && transitType != "" && !slices.Contains(types, transitType) {
+			errs = append(errs, fmt.Errorf("Semantic tag %q does not apply to %s boarding passes", tag, transitType))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,270 @@
+package passkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// Semantic tags modelled on Apple's event ticket and boarding pass samples.
+// The keys keep the samples' order, which is not always the field order.
+const (
+	eventTicketSemantics = `{
+		"artistIDs": ["1234567890"],
+		"eventEndDate": "2024-06-20T22:00:00-07:00",
+		"eventName": "Golden State Warriors vs. Los Angeles Lakers",
+		"eventStartDate": "2024-06-20T19:00:00-07:00",
+		"eventType": "PKEventTypeSports",
+		"homeTeamAbbreviation": "GSW",
+		"homeTeamLocation": "San Francisco",
+		"homeTeamName": "Warriors",
+		"awayTeamAbbreviation": "LAL",
+		"awayTeamLocation": "Los Angeles",
+		"awayTeamName": "Lakers",
+		"leagueAbbreviation": "NBA",
+		"leagueName": "National Basketball Association",
+		"seats": [
+			{
+				"seatDescription": "Courtside",
+				"seatNumber": "12",
+				"seatRow": "A",
+				"seatSection": "101",
+				"seatSectionColor": "rgb(29, 66, 138)"
+			}
+		],
+		"sportName": "Basketball",
+		"venueEntrance": "Gate 3",
+		"venueLocation": {
+			"latitude": 37.768,
+			"longitude": -122.3877
+		},
+		"venueName": "Chase Center",
+		"wifiAccess": [
+			{
+				"password": "warriors2024",
+				"ssid": "ChaseCenterGuest"
+			}
+		]
+	}`
+
+	boardingPassSemantics = `{
+		"airlineCode": "UA",
+		"boardingGroup": "2",
+		"departureAirportCode": "SFO",
+		"departureGate": "G2",
+		"departureLocation": {
+			"latitude": 37.6189,
+			"longitude": -122.3748
+		},
+		"departureTerminal": "3",
+		"destinationAirportCode": "LHR",
+		"flightCode": "UA901",
+		"flightNumber": 901,
+		"originalDepartureDate": "2024-07-01T17:25:00-07:00",
+		"passengerName": {
+			"familyName": "Appleseed",
+			"givenName": "Johnny"
+		},
+		"seats": [
+			{
+				"seatIdentifier": "23A",
+				"seatType": "Economy"
+			},
+			{
+				"seatIdentifier": "23B",
+				"seatType": "Economy"
+			}
+		],
+		"totalPrice": {
+			"amount": "742.50",
+			"currencyCode": "USD"
+		}
+	}`
+)
+
+// sortedSemantics reorders the sample's keys into field order so the encoded
+// output can be compared byte for byte.
+func sortedSemantics(t *testing.T, sample string) []byte {
+	t.Helper()
+
+	var s SemanticTags
+	if err := json.Unmarshal([]byte(sample), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(sample), &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, key := range s.setTags() {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, fields[key]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf.WriteByte('}')
+
+	if len(fields) != len(s.setTags()) {
+		t.Fatalf("sample has %d keys, %d decoded", len(fields), len(s.setTags()))
+	}
+
+	return buf.Bytes()
+}
+
+func TestSemanticTagsRoundTrip(t *testing.T) {
+	for name, sample := range map[string]string{
+		"event ticket":  eventTicketSemantics,
+		"boarding pass": boardingPassSemantics,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var s SemanticTags
+			if err := json.Unmarshal([]byte(sample), &s); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.Marshal(&s)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := sortedSemantics(t, sample); !bytes.Equal(got, want) {
+				t.Errorf("encoded\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestSemanticTagsDecode(t *testing.T) {
+	var s SemanticTags
+	if err := json.Unmarshal([]byte(boardingPassSemantics), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.FlightNumber != 901 || s.DepartureAirportCode != "SFO" || s.TotalPrice.Amount != "742.50" {
+		t.Errorf("decoded %+v", s)
+	}
+	if len(s.Seats) != 2 || s.Seats[1].SeatIdentifier != "23B" {
+		t.Errorf("seats = %+v", s.Seats)
+	}
+	if s.PassengerName == nil || s.PassengerName.GivenName != "Johnny" {
+		t.Errorf("passengerName = %+v", s.PassengerName)
+	}
+	if want := "2024-07-01T17:25:00-07:00"; s.OriginalDepartureDate == nil || s.OriginalDepartureDate.String() != want {
+		t.Errorf("originalDepartureDate = %v, want %s", s.OriginalDepartureDate, want)
+	}
+}
+
+func TestSemanticTagsLegacySingleValues(t *testing.T) {
+	legacy := `{
+		"artistIDs": "1234567890",
+		"seats": {
+			"seatDescription": "Courtside",
+			"seatNumber": "12",
+			"seatRow": "A",
+			"seatSection": "101",
+			"seatSectionColor": "rgb(29, 66, 138)"
+		},
+		"wifiAccess": {
+			"password": "warriors2024",
+			"ssid": "ChaseCenterGuest"
+		}
+	}`
+
+	array := `{
+		"artistIDs": ["1234567890"],
+		"seats": [
+			{
+				"seatDescription": "Courtside",
+				"seatNumber": "12",
+				"seatRow": "A",
+				"seatSection": "101",
+				"seatSectionColor": "rgb(29, 66, 138)"
+			}
+		],
+		"wifiAccess": [
+			{
+				"password": "warriors2024",
+				"ssid": "ChaseCenterGuest"
+			}
+		]
+	}`
+
+	var fromLegacy, fromArray SemanticTags
+	if err := json.Unmarshal([]byte(legacy), &fromLegacy); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(array), &fromArray); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(&fromLegacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := json.Marshal(&fromArray)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("legacy form encoded\n%s\nwant\n%s", got, want)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(array)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, compact.Bytes()) {
+		t.Errorf("legacy form encoded\n%s\nwant the array form\n%s", got, compact.Bytes())
+	}
+}
+
+func TestSemanticTagsLegacyStrictDecode(t *testing.T) {
+	data := `{
+		"formatVersion": 1,
+		"passTypeIdentifier": "pass.com.example",
+		"serialNumber": "ABC123",
+		"teamIdentifier": "ABCDE12345",
+		"organizationName": "Example",
+		"description": "Event ticket",
+		"eventTicket": {},
+		"semantics": {
+			"seats": {"seatNumber": "12"},
+			"wifiAccess": {"ssid": "Guest", "password": "secret"}
+		}
+	}`
+
+	p, err := DecodePass([]byte(data), DecodeStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Semantics.Seats) != 1 || p.Semantics.Seats[0].SeatNumber != "12" {
+		t.Errorf("seats = %+v", p.Semantics.Seats)
+	}
+	if len(p.Semantics.WifiAccess) != 1 || p.Semantics.WifiAccess[0].Ssid != "Guest" {
+		t.Errorf("wifiAccess = %+v", p.Semantics.WifiAccess)
+	}
+}
+
+func TestSemanticTagsInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"seats": "12A"}`,
+		`{"artistIDs": 12}`,
+		`{"wifiAccess": [1]}`,
+	} {
+		var s SemanticTags
+		if err := json.Unmarshal([]byte(data), &s); err == nil {
+			t.Errorf("expected an error decoding %s", data)
+		}
+	}
+}
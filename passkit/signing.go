@@ -0,0 +1,532 @@
+package passkit
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+)
+
+var (
+	oidData                   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidAttributeContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttributeMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttributeSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidSHA1                   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256                 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSAEncryption          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSHA256WithRSA          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidECDSAWithSHA256        = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"optional"`
+}
+
+type issuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+type signerInfo struct {
+	Version            int
+	IssuerAndSerial    issuerAndSerial
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      contentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      []signerInfo `asn1:"set"`
+}
+
+// CertSigner signs manifests with a Pass Type ID (or Order Type ID)
+// certificate, producing the detached PKCS #7 signature Wallet expects.
+type CertSigner struct {
+	Certificate *x509.Certificate
+	Key         crypto.Signer
+
+	// Intermediates are embedded in the signature, which must include the
+	// Apple WWDR intermediate certificate that issued Certificate.
+	Intermediates []*x509.Certificate
+}
+
+func NewCertSigner(cert *x509.Certificate, key crypto.Signer, intermediates ...*x509.Certificate) (*CertSigner, error) {
+	if cert == nil {
+		return nil, errors.New("Certificate can not be empty")
+	}
+
+	if key == nil {
+		return nil, errors.New("Key can not be empty")
+	}
+
+	switch key.Public().(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return nil, fmt.Errorf("Unsupported key type %T", key.Public())
+	}
+
+	return &CertSigner{Certificate: cert, Key: key, Intermediates: intermediates}, nil
+}
+
+func parsePEMCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("No PEM certificate found")
+	}
+
+	return certs, nil
+}
+
+func parsePEMKey(data []byte) (crypto.Signer, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("No PEM private key found")
+		}
+
+		var (
+			key any
+			err error
+		)
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, errors.New("Encrypted private keys are not supported, decrypt the key first")
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("Unsupported key type %T", key)
+		}
+
+		return signer, nil
+	}
+}
+
+// ParseCertSigner builds a signer from PEM data: the certificate, its
+// private key (which may share the same data) and the WWDR intermediate.
+func ParseCertSigner(certPEM, keyPEM, wwdrPEM []byte) (*CertSigner, error) {
+	certs, err := parsePEMCertificates(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("Certificate: %w", err)
+	}
+
+	key, err := parsePEMKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("Key: %w", err)
+	}
+
+	intermediates := certs[1:]
+	if len(wwdrPEM) > 0 {
+		wwdr, err := parsePEMCertificates(wwdrPEM)
+		if err != nil {
+			return nil, fmt.Errorf("WWDR certificate: %w", err)
+		}
+		intermediates = append(intermediates, wwdr...)
+	}
+
+	return NewCertSigner(certs[0], key, intermediates...)
+}
+
+// LoadCertSigner is ParseCertSigner reading PEM files. wwdrFile may be empty
+// if certFile already holds the chain.
+func LoadCertSigner(certFile, keyFile, wwdrFile string) (*CertSigner, error) {
+	var data [3][]byte
+	for i, name := range []string{certFile, keyFile, wwdrFile} {
+		if name == "" {
+			continue
+		}
+
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = b
+	}
+
+	return ParseCertSigner(data[0], data[1], data[2])
+}
+
+func marshalAttribute(oid asn1.ObjectIdentifier, value any) ([]byte, error) {
+	v, err := asn1.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(attribute{Type: oid, Values: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: v}})
+}
+
+// Sign returns the DER encoded, detached PKCS #7 signature of manifest.
+func (s *CertSigner) Sign(manifest []byte) ([]byte, error) {
+	digest := sha256.Sum256(manifest)
+
+	var attrs [][]byte
+	for _, a := range []struct {
+		oid   asn1.ObjectIdentifier
+		value any
+	}{
+		{oidAttributeContentType, oidData},
+		{oidAttributeSigningTime, time.Now().UTC()},
+		{oidAttributeMessageDigest, digest[:]},
+	} {
+		b, err := marshalAttribute(a.oid, a.value)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, b)
+	}
+
+	// DER sorts the elements of a SET OF by their encoding.
+	sort.Slice(attrs, func(i, j int) bool { return bytes.Compare(attrs[i], attrs[j]) < 0 })
+	attrBytes := bytes.Join(attrs, nil)
+
+	toSign, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attrBytes})
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(toSign)
+
+	signature, err := s.Key.Sign(rand.Reader, h[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	sigAlg := oidRSAEncryption
+	if _, ok := s.Key.Public().(*ecdsa.PublicKey); ok {
+		sigAlg = oidECDSAWithSHA256
+	}
+
+	var certs []byte
+	for _, c := range append([]*x509.Certificate{s.Certificate}, s.Intermediates...) {
+		certs = append(certs, c.Raw...)
+	}
+
+	sha256Alg := pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Alg},
+		ContentInfo:      contentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certs},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			IssuerAndSerial:    issuerAndSerial{Issuer: asn1.RawValue{FullBytes: s.Certificate.RawIssuer}, Serial: s.Certificate.SerialNumber},
+			DigestAlgorithm:    sha256Alg,
+			SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrBytes},
+			SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: sigAlg, Parameters: asn1.NullRawValue},
+			Signature:          signature,
+		}},
+	}
+	if sigAlg.Equal(oidECDSAWithSHA256) {
+		sd.SignerInfos[0].SignatureAlgorithm.Parameters = asn1.RawValue{}
+	}
+
+	inner, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, err
+	}
+
+	// The content is [0] EXPLICIT, which RawValue does not apply by itself.
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner},
+	})
+}
+
+// parseSignedData decodes a PKCS #7 SignedData, walking its elements by hand
+// since its optional parts are implicitly tagged.
+func parseSignedData(der []byte) (*signedData, error) {
+	var ci contentInfo
+	if rest, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, errors.New("Trailing data after signature")
+	}
+
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, errors.New("Signature is not PKCS #7 signed data")
+	}
+
+	var seq asn1.RawValue
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &seq); err != nil {
+		return nil, err
+	}
+
+	sd := &signedData{}
+	rest := seq.Bytes
+	next := func(v any, params string) error {
+		var err error
+		rest, err = asn1.UnmarshalWithParams(rest, v, params)
+		return err
+	}
+
+	if err := next(&sd.Version, ""); err != nil {
+		return nil, err
+	}
+	if err := next(&sd.DigestAlgorithms, "set"); err != nil {
+		return nil, err
+	}
+	if err := next(&sd.ContentInfo, ""); err != nil {
+		return nil, err
+	}
+
+	for len(rest) > 0 {
+		var el asn1.RawValue
+		if err := next(&el, ""); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case el.Class == asn1.ClassContextSpecific && el.Tag == 0:
+			sd.Certificates = el
+		case el.Class == asn1.ClassContextSpecific && el.Tag == 1:
+			// CRLs are not used.
+		case el.Class == asn1.ClassUniversal && el.Tag == asn1.TagSet:
+			infos, err := parseSignerInfos(el.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			sd.SignerInfos = infos
+		}
+	}
+
+	return sd, nil
+}
+
+func parseSignerInfos(data []byte) ([]signerInfo, error) {
+	var infos []signerInfo
+	for len(data) > 0 {
+		var seq asn1.RawValue
+		var err error
+		if data, err = asn1.Unmarshal(data, &seq); err != nil {
+			return nil, err
+		}
+
+		var si signerInfo
+		rest := seq.Bytes
+		for i, v := range []any{&si.Version, &si.IssuerAndSerial, &si.DigestAlgorithm} {
+			if rest, err = asn1.Unmarshal(rest, v); err != nil {
+				return nil, fmt.Errorf("Signer info element %d: %w", i, err)
+			}
+		}
+
+		var el asn1.RawValue
+		if rest, err = asn1.Unmarshal(rest, &el); err != nil {
+			return nil, err
+		}
+		if el.Class == asn1.ClassContextSpecific && el.Tag == 0 {
+			si.SignedAttrs = el
+			if rest, err = asn1.Unmarshal(rest, &si.SignatureAlgorithm); err != nil {
+				return nil, err
+			}
+		} else if _, err = asn1.Unmarshal(el.FullBytes, &si.SignatureAlgorithm); err != nil {
+			return nil, err
+		}
+
+		if _, err = asn1.Unmarshal(rest, &si.Signature); err != nil {
+			return nil, err
+		}
+		infos = append(infos, si)
+	}
+
+	return infos, nil
+}
+
+func signatureAlgorithm(digest, sig asn1.ObjectIdentifier) (x509.SignatureAlgorithm, crypto.Hash, error) {
+	var hash crypto.Hash
+	switch {
+	case digest.Equal(oidSHA1):
+		hash = crypto.SHA1
+	case digest.Equal(oidSHA256):
+		hash = crypto.SHA256
+	default:
+		return 0, 0, fmt.Errorf("Unsupported digest algorithm %s", digest)
+	}
+
+	switch {
+	case sig.Equal(oidRSAEncryption) && hash == crypto.SHA1:
+		return x509.SHA1WithRSA, hash, nil
+	case sig.Equal(oidRSAEncryption), sig.Equal(oidSHA256WithRSA):
+		return x509.SHA256WithRSA, hash, nil
+	case sig.Equal(oidECDSAWithSHA256):
+		return x509.ECDSAWithSHA256, hash, nil
+	}
+
+	return 0, 0, fmt.Errorf("Unsupported signature algorithm %s", sig)
+}
+
+// VerifySignature checks a detached PKCS #7 signature of manifest and
+// returns the certificates it carries, the signing certificate first. If
+// roots is not nil, the signing certificate must also chain up to one of
+// them through the embedded intermediates.
+func VerifySignature(manifest, signature []byte, roots *x509.CertPool) ([]*x509.Certificate, error) {
+	sd, err := parseSignedData(signature)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid signature: %w", err)
+	}
+
+	if len(sd.SignerInfos) != 1 {
+		return nil, fmt.Errorf("Signature has %d signers, expected 1", len(sd.SignerInfos))
+	}
+	si := sd.SignerInfos[0]
+
+	var certs []*x509.Certificate
+	if len(sd.Certificates.Bytes) > 0 {
+		if certs, err = x509.ParseCertificates(sd.Certificates.Bytes); err != nil {
+			return nil, fmt.Errorf("Invalid signature certificates: %w", err)
+		}
+	}
+
+	var signer *x509.Certificate
+	for i, c := range certs {
+		if bytes.Equal(c.RawIssuer, si.IssuerAndSerial.Issuer.FullBytes) && c.SerialNumber.Cmp(si.IssuerAndSerial.Serial) == 0 {
+			signer = c
+			certs[0], certs[i] = certs[i], certs[0]
+			break
+		}
+	}
+	if signer == nil {
+		return nil, errors.New("Signature does not include the signing certificate")
+	}
+
+	algo, hash, err := signatureAlgorithm(si.DigestAlgorithm.Algorithm, si.SignatureAlgorithm.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash.New()
+	h.Write(manifest)
+	digest := h.Sum(nil)
+
+	signed := manifest
+	if len(si.SignedAttrs.Bytes) > 0 {
+		if err := checkMessageDigest(si.SignedAttrs.Bytes, digest); err != nil {
+			return nil, err
+		}
+
+		signed, err = asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: si.SignedAttrs.Bytes})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := signer.CheckSignature(algo, signed, si.Signature); err != nil {
+		return nil, fmt.Errorf("Signature does not match: %w", err)
+	}
+
+	if roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, c := range certs[1:] {
+			intermediates.AddCert(c)
+		}
+
+		_, err := signer.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			return certs, fmt.Errorf("Signing certificate is not trusted: %w", err)
+		}
+	}
+
+	return certs, nil
+}
+
+func checkMessageDigest(attrs []byte, digest []byte) error {
+	for len(attrs) > 0 {
+		var a attribute
+		var err error
+		if attrs, err = asn1.Unmarshal(attrs, &a); err != nil {
+			return err
+		}
+
+		if !a.Type.Equal(oidAttributeMessageDigest) {
+			continue
+		}
+
+		var got []byte
+		if _, err := asn1.Unmarshal(a.Values.Bytes, &got); err != nil {
+			return err
+		}
+
+		if !bytes.Equal(got, digest) {
+			return errors.New("Signature was made for a different manifest")
+		}
+
+		return nil
+	}
+
+	return errors.New("Signature has no message digest")
+}
+
+// manifestHash picks the digest algorithm of a manifest from its digests:
+// SHA-1 for passes and SHA-256 for orders.
+func manifestHash(digests map[string]string) (crypto.Hash, error) {
+	for _, d := range digests {
+		switch len(d) {
+		case 2 * sha1.Size:
+			return crypto.SHA1, nil
+		case 2 * sha256.Size:
+			return crypto.SHA256, nil
+		}
+
+		return 0, fmt.Errorf("Unsupported digest %q", d)
+	}
+
+	return crypto.SHA1, nil
+}
@@ -0,0 +1,291 @@
+package passkit
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"hash"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testPKI struct {
+	root         *x509.Certificate
+	intermediate *x509.Certificate
+	leaf         *x509.Certificate
+	key          crypto.Signer
+}
+
+func newTestCertificate(t *testing.T, name string, serial int64, pub crypto.PublicKey, parent *x509.Certificate, parentKey crypto.Signer, ca bool) *x509.Certificate {
+	t.Helper()
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: name, OrganizationalUnit: []string{"ABCDE12345"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  ca,
+	}
+	if ca {
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	}
+	if parent == nil {
+		parent = tmpl
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+// newTestPKI issues a pass certificate for leafKey through an intermediate
+// standing in for Apple WWDR.
+func newTestPKI(t *testing.T, leafKey crypto.Signer) *testPKI {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pki := &testPKI{key: leafKey}
+	pki.root = newTestCertificate(t, "Test Root", 1, rootKey.Public(), nil, rootKey, true)
+	pki.intermediate = newTestCertificate(t, "Test WWDR", 2, interKey.Public(), pki.root, rootKey, true)
+	pki.leaf = newTestCertificate(t, "Pass Type ID: pass.com.example", 3, leafKey.Public(), pki.intermediate, interKey, false)
+
+	return pki
+}
+
+func (pki *testPKI) roots() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(pki.root)
+
+	return pool
+}
+
+func (pki *testPKI) signer(t *testing.T) *CertSigner {
+	t.Helper()
+
+	s, err := NewCertSigner(pki.leaf, pki.key, pki.intermediate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func testKeys(t *testing.T) map[string]crypto.Signer {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]crypto.Signer{"RSA": rsaKey, "ECDSA": ecKey}
+}
+
+func TestSignVerify(t *testing.T) {
+	manifest := []byte(`{"pass.json":"0123456789abcdef0123456789abcdef01234567"}`)
+
+	for name, key := range testKeys(t) {
+		t.Run(name, func(t *testing.T) {
+			pki := newTestPKI(t, key)
+
+			signature, err := pki.signer(t).Sign(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			certs, err := VerifySignature(manifest, signature, pki.roots())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(certs) != 2 || !certs[0].Equal(pki.leaf) || !certs[1].Equal(pki.intermediate) {
+				t.Errorf("certificates = %d, want the pass certificate then the intermediate", len(certs))
+			}
+
+			if _, err := VerifySignature(manifest, signature, nil); err != nil {
+				t.Errorf("verifying without roots: %v", err)
+			}
+
+			tampered := bytes.Replace(manifest, []byte("0123"), []byte("3210"), 1)
+			if _, err := VerifySignature(tampered, signature, nil); err == nil {
+				t.Error("expected an error for a different manifest")
+			}
+
+			other := newTestPKI(t, key)
+			if _, err := VerifySignature(manifest, signature, other.roots()); err == nil || !strings.Contains(err.Error(), "not trusted") {
+				t.Errorf("untrusted root error = %v", err)
+			}
+
+			corrupt := bytes.Clone(signature)
+			corrupt[len(corrupt)-1] ^= 0xff
+			if _, err := VerifySignature(manifest, corrupt, nil); err == nil {
+				t.Error("expected an error for a corrupted signature")
+			}
+		})
+	}
+}
+
+// TestSignOpenSSL checks the signature with an independent PKCS #7
+// implementation when openssl is installed.
+func TestSignOpenSSL(t *testing.T) {
+	openssl, err := exec.LookPath("openssl")
+	if err != nil {
+		t.Skip("openssl not found")
+	}
+
+	manifest := []byte(`{"pass.json":"0123456789abcdef0123456789abcdef01234567"}`)
+	for name, key := range testKeys(t) {
+		t.Run(name, func(t *testing.T) {
+			pki := newTestPKI(t, key)
+
+			signature, err := pki.signer(t).Sign(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dir := t.TempDir()
+			write := func(name string, data []byte) string {
+				path := filepath.Join(dir, name)
+				if err := os.WriteFile(path, data, 0o600); err != nil {
+					t.Fatal(err)
+				}
+				return path
+			}
+
+			out, err := exec.Command(openssl, "smime", "-verify", "-binary", "-inform", "DER",
+				"-in", write("signature", signature),
+				"-content", write("manifest.json", manifest),
+				"-CAfile", write("root.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: pki.root.Raw})),
+				"-purpose", "any",
+				"-out", os.DevNull,
+			).CombinedOutput()
+			if err != nil {
+				t.Errorf("openssl smime -verify: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestParseCertSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pki := newTestPKI(t, key)
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: pki.leaf.Raw})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	wwdrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: pki.intermediate.Raw})
+
+	s, err := ParseCertSigner(certPEM, keyPEM, wwdrPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Certificate.Equal(pki.leaf) || len(s.Intermediates) != 1 || !s.Intermediates[0].Equal(pki.intermediate) {
+		t.Error("signer does not hold the certificate and intermediate")
+	}
+
+	// The key and chain may also share one file.
+	combined := append(append(bytes.Clone(certPEM), wwdrPEM...), keyPEM...)
+	if s, err = ParseCertSigner(combined, combined, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Intermediates) != 1 {
+		t.Errorf("%d intermediates, want 1", len(s.Intermediates))
+	}
+
+	if _, err := ParseCertSigner(certPEM, wwdrPEM, nil); err == nil {
+		t.Error("expected an error without a private key")
+	}
+}
+
+func TestSignedArchive(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pki := newTestPKI(t, key)
+
+	files := map[string][]byte{
+		"pass.json":             []byte(`{"formatVersion":1}`),
+		"icon.png":              []byte("icon"),
+		"en.lproj/pass.strings": []byte(`"a" = "b";`),
+	}
+
+	for name, newHash := range map[string]func() hash.Hash{"SHA-1": sha1.New, "SHA-256": sha256.New} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteSignedArchive(&buf, files, newHash, pki.signer(t)); err != nil {
+				t.Fatal(err)
+			}
+
+			read, err := ReadArchive(buf.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := VerifyArchive(read, pki.roots()); err != nil {
+				t.Fatal(err)
+			}
+
+			read["icon.png"] = []byte("changed")
+			read["extra.png"] = []byte("extra")
+			delete(read, "en.lproj/pass.strings")
+
+			_, err = VerifyArchive(read, pki.roots())
+			for _, want := range []string{
+				"File icon.png does not match its manifest digest",
+				"File extra.png is not in the manifest",
+				"File en.lproj/pass.strings of the manifest is missing",
+			} {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("error %v does not report %q", err, want)
+				}
+			}
+		})
+	}
+
+	if err := WriteSignedArchive(&bytes.Buffer{}, map[string][]byte{"signature": nil}, sha1.New, pki.signer(t)); err == nil {
+		t.Error("expected an error for a generated file name")
+	}
+}
@@ -0,0 +1,182 @@
+package passkit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// StringsFile is a localization table in the .strings format of the
+// xx.lproj/pass.strings files of a pass.
+type StringsFile struct {
+	Keys   []string
+	Values map[string]string
+	utf16  bool
+}
+
+var errUnterminatedString = errors.New("Unterminated string in strings file")
+
+func decodeStringsText(data []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}), bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		little := data[0] == 0xff
+		units := make([]uint16, 0, len(data)/2)
+		for i := 2; i+1 < len(data); i += 2 {
+			if little {
+				units = append(units, uint16(data[i])|uint16(data[i+1])<<8)
+			} else {
+				units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+			}
+		}
+		return string(utf16.Decode(units)), true
+	}
+
+	return string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))), false
+}
+
+// ParseStringsFile parses a UTF-8 or UTF-16 .strings file.
+func ParseStringsFile(data []byte) (*StringsFile, error) {
+	text, isUTF16 := decodeStringsText(data)
+	f := &StringsFile{Values: make(map[string]string), utf16: isUTF16}
+
+	var tokens []string
+	for i := 0; i < len(text); {
+		switch c := text[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("Unterminated comment in strings file")
+			}
+			i += end + 4
+		case strings.HasPrefix(text[i:], "//"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				end = len(text) - i
+			}
+			i += end
+		case c == '=' || c == ';':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			s, n, err := unquoteStringsToken(text[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, "\""+s)
+			i += n
+		default:
+			return nil, fmt.Errorf("Unexpected %q in strings file", c)
+		}
+	}
+
+	for len(tokens) > 0 {
+		if len(tokens) < 4 || tokens[1] != "=" || tokens[3] != ";" || tokens[0][0] != '"' || tokens[2][0] != '"' {
+			return nil, errors.New("Strings file entries must look like \"key\" = \"value\";")
+		}
+
+		key, value := tokens[0][1:], tokens[2][1:]
+		if _, ok := f.Values[key]; !ok {
+			f.Keys = append(f.Keys, key)
+		}
+		f.Values[key] = value
+		tokens = tokens[4:]
+	}
+
+	return f, nil
+}
+
+// unquoteStringsToken reads the quoted string at the start of s and returns
+// its value and length.
+func unquoteStringsToken(s string) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", 0, errUnterminatedString
+			}
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'U', 'u':
+				r, n, err := unquoteUnicodeEscape(s[i-1:])
+				if err != nil {
+					return "", 0, err
+				}
+				b.WriteRune(r)
+				i += n - 2
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", 0, errUnterminatedString
+}
+
+// unquoteUnicodeEscape reads the \Uxxxx escape at the start of s, with its
+// low surrogate if it is the high half of a pair, and returns the rune and
+// the length read.
+func unquoteUnicodeEscape(s string) (rune, int, error) {
+	unit := func(s string) (rune, bool) {
+		if len(s) < 6 || s[0] != '\\' || (s[1] != 'U' && s[1] != 'u') {
+			return 0, false
+		}
+		n, err := strconv.ParseUint(s[2:6], 16, 16)
+		return rune(n), err == nil
+	}
+
+	r, ok := unit(s)
+	if !ok {
+		return 0, 0, errors.New("Invalid \\U escape in strings file")
+	}
+
+	if utf16.IsSurrogate(r) {
+		if low, ok := unit(s[6:]); ok {
+			if pair := utf16.DecodeRune(r, low); pair != utf8.RuneError {
+				return pair, 12, nil
+			}
+		}
+		return utf8.RuneError, 6, nil
+	}
+
+	return r, 6, nil
+}
+
+var stringsEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+// Bytes encodes the table in the encoding it was parsed from.
+func (f *StringsFile) Bytes() []byte {
+	var b strings.Builder
+	for _, key := range f.Keys {
+		fmt.Fprintf(&b, "\"%s\" = \"%s\";\n", stringsEscaper.Replace(key), stringsEscaper.Replace(f.Values[key]))
+	}
+
+	if !f.utf16 {
+		return []byte(b.String())
+	}
+
+	units := utf16.Encode([]rune(b.String()))
+	out := make([]byte, 2, 2+2*len(units))
+	out[0], out[1] = 0xff, 0xfe
+	for _, u := range units {
+		out = append(out, byte(u), byte(u>>8))
+	}
+
+	return out
+}
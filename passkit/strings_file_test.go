@@ -0,0 +1,80 @@
+package passkit
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func utf16File(s string, bigEndian bool) []byte {
+	out := []byte{0xff, 0xfe}
+	if bigEndian {
+		out = []byte{0xfe, 0xff}
+	}
+
+	for _, u := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			out = append(out, byte(u>>8), byte(u))
+		} else {
+			out = append(out, byte(u), byte(u>>8))
+		}
+	}
+
+	return out
+}
+
+func TestParseStringsFile(t *testing.T) {
+	text := `/* Gate label */
+"gate" = "Gate";
+// Escapes
+"quote \"x\"" = "line\none\ttab \\ back";
+"unicode" = "Caf\U00e9 \U00E9 \Ud83d\Ude00 😀";
+"gate" = "Gate again";
+`
+	want := map[string]string{
+		"gate":      "Gate again",
+		`quote "x"`: "line\none\ttab \\ back",
+		"unicode":   "Café é 😀 😀",
+	}
+
+	for name, data := range map[string][]byte{
+		"UTF-8":     []byte(text),
+		"UTF-8 BOM": append([]byte("\xef\xbb\xbf"), text...),
+		"UTF-16LE":  utf16File(text, false),
+		"UTF-16BE":  utf16File(text, true),
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, err := ParseStringsFile(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(f.Values, want) {
+				t.Errorf("values = %q, want %q", f.Values, want)
+			}
+			if !reflect.DeepEqual(f.Keys, []string{"gate", `quote "x"`, "unicode"}) {
+				t.Errorf("keys = %q", f.Keys)
+			}
+
+			again, err := ParseStringsFile(f.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(again.Values, f.Values) || !reflect.DeepEqual(again.Keys, f.Keys) {
+				t.Errorf("round trip = %q", again.Values)
+			}
+
+			if isUTF16 := strings.HasPrefix(name, "UTF-16"); isUTF16 != bytes.HasPrefix(f.Bytes(), []byte{0xff, 0xfe}) {
+				t.Errorf("round trip changed the encoding")
+			}
+		})
+	}
+
+	for _, bad := range []string{`"a" = "b"`, `"a" = "b`, `"a" "b";`, `/* open`, `"a" = "\U00g1";`, `a = "b";`} {
+		if _, err := ParseStringsFile([]byte(bad)); err == nil {
+			t.Errorf("expected an error for %s", bad)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package passkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Template is a pass designed in a directory: a pass.json skeleton whose
+// string values may hold text/template placeholders such as {{.Name}}, the
+// images, and xx.lproj/pass.strings translations that may hold placeholders
+// too.
+type Template struct {
+	pass      any
+	assets    map[string][]byte
+	strings   map[string]*StringsFile
+	templates map[string]*template.Template
+}
+
+func LoadTemplate(dir string) (*Template, error) {
+	return LoadTemplateFS(os.DirFS(dir))
+}
+
+// LoadTemplateFS reads a template once and parses all its placeholders, so
+// that errors in the template surface before the first Render.
+func LoadTemplateFS(fsys fs.FS) (*Template, error) {
+	t := &Template{
+		assets:    make(map[string][]byte),
+		strings:   make(map[string]*StringsFile),
+		templates: make(map[string]*template.Template),
+	}
+
+	raw, err := fs.ReadFile(fsys, "pass.json")
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&t.pass); err != nil {
+		return nil, fmt.Errorf("Template pass.json: %w", err)
+	}
+
+	if err := t.parse(t.pass); err != nil {
+		return nil, fmt.Errorf("Template pass.json: %w", err)
+	}
+
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		base := path.Base(name)
+		if strings.HasPrefix(base, ".") && name != "." {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case d.IsDir(), name == "pass.json", name == "manifest.json", name == "signature":
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		if base != "pass.strings" {
+			t.assets[name] = data
+			return nil
+		}
+
+		sf, err := ParseStringsFile(data)
+		if err != nil {
+			return fmt.Errorf("Template %s: %w", name, err)
+		}
+
+		for _, key := range sf.Keys {
+			if err := t.parse(sf.Values[key]); err != nil {
+				return fmt.Errorf("Template %s: %w", name, err)
+			}
+		}
+		t.strings[name] = sf
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// parse compiles the placeholders of every string in node.
+func (t *Template) parse(node any) error {
+	switch v := node.(type) {
+	case map[string]any:
+		for _, el := range v {
+			if err := t.parse(el); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, el := range v {
+			if err := t.parse(el); err != nil {
+				return err
+			}
+		}
+	case string:
+		if !strings.Contains(v, "{{") || t.templates[v] != nil {
+			return nil
+		}
+
+		tmpl, err := template.New("").Option("missingkey=error").Parse(v)
+		if err != nil {
+			return err
+		}
+		t.templates[v] = tmpl
+	}
+
+	return nil
+}
+
+func (t *Template) execute(s string, data any) (string, error) {
+	tmpl := t.templates[s]
+	if tmpl == nil {
+		return s, nil
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// render returns a copy of node with its placeholders substituted.
+func (t *Template) render(node any, data any) (any, error) {
+	switch v := node.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, el := range v {
+			r, err := t.render(el, data)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = r
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, el := range v {
+			r, err := t.render(el, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	case string:
+		return t.execute(v, data)
+	}
+
+	return node, nil
+}
+
+// Render fills the placeholders with data and returns the pass together with
+// the files to archive with it. The images are shared between renders and
+// must not be modified.
+func (t *Template) Render(data any) (*Pass, map[string][]byte, error) {
+	tree, err := t.render(t.pass, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Rendering pass.json: %w", err)
+	}
+
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p, err := DecodePass(raw, DecodeLenient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Rendering pass.json: %w", err)
+	}
+
+	files := make(map[string][]byte, len(t.assets)+len(t.strings))
+	for name, data := range t.assets {
+		files[name] = data
+	}
+
+	for name, sf := range t.strings {
+		out := &StringsFile{Keys: sf.Keys, Values: make(map[string]string, len(sf.Values)), utf16: sf.utf16}
+		for _, key := range sf.Keys {
+			if out.Values[key], err = t.execute(sf.Values[key], data); err != nil {
+				return nil, nil, fmt.Errorf("Rendering %s: %w", name, err)
+			}
+		}
+		files[name] = out.Bytes()
+	}
+
+	return p, files, nil
+}
@@ -0,0 +1,105 @@
+package passkit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const templatePassJSON = `{
+	"formatVersion": 1,
+	"passTypeIdentifier": "pass.com.example",
+	"serialNumber": "{{.Serial}}",
+	"teamIdentifier": "ABCDE12345",
+	"organizationName": "Example",
+	"description": "Ticket for {{.Name}}",
+	"eventTicket": {
+		"primaryFields": [{"key": "name", "label": "NAME", "value": "{{.Name}}"}],
+		"secondaryFields": [{"key": "seat", "label": "Seat", "value": 12}]
+	},
+	"barcodes": [{"format": "PKBarcodeFormatQR", "message": "TICKET-{{.Serial}}", "messageEncoding": "iso-8859-1"}],
+	"semantics": {"eventName": "{{.Event}}"}
+}`
+
+func TestTemplateRender(t *testing.T) {
+	tmpl, err := LoadTemplateFS(fstest.MapFS{
+		"pass.json":             {Data: []byte(templatePassJSON)},
+		"icon.png":              {Data: []byte("icon")},
+		"en.lproj/pass.strings": {Data: []byte(`"NAME" = "Name of {{.Name}}";`)},
+		".DS_Store":             {Data: []byte("hidden")},
+		"signature":             {Data: []byte("old")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := map[string]string{"Serial": "42", "Name": "Ann", "Event": "Concert"}
+	p, files, err := tmpl.Render(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.SerialNumber != "42" || p.Description != "Ticket for Ann" {
+		t.Errorf("serial %q, description %q", p.SerialNumber, p.Description)
+	}
+	if v := p.EventTicket.PrimaryFields[0].Value; v == nil || v.String() != "Ann" {
+		t.Errorf("primary field value = %v", v)
+	}
+	if v := p.EventTicket.SecondaryFields[0].Value; v == nil || !v.IsNumber() || v.Number() != 12 {
+		t.Errorf("numeric field value = %v", v)
+	}
+	if p.Barcodes[0].Message != "TICKET-42" {
+		t.Errorf("barcode message = %q", p.Barcodes[0].Message)
+	}
+	if p.Semantics == nil || p.Semantics.EventName != "Concert" {
+		t.Errorf("semantics = %+v", p.Semantics)
+	}
+
+	if len(files) != 2 || string(files["icon.png"]) != "icon" {
+		t.Errorf("files = %q", keys(files))
+	}
+	sf, err := ParseStringsFile(files["en.lproj/pass.strings"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sf.Values["NAME"] != "Name of Ann" {
+		t.Errorf("localized label = %q", sf.Values["NAME"])
+	}
+
+	_, _, err = tmpl.Render(map[string]string{"Serial": "43", "Name": "Bob"})
+	if err == nil || !strings.Contains(err.Error(), "Event") {
+		t.Errorf("missing key error = %v", err)
+	}
+}
+
+func TestLoadTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{name: "no pass.json", files: fstest.MapFS{"icon.png": {Data: []byte("icon")}}, want: "pass.json"},
+		{name: "invalid JSON", files: fstest.MapFS{"pass.json": {Data: []byte(`{`)}}, want: "Template pass.json"},
+		{name: "invalid placeholder", files: fstest.MapFS{"pass.json": {Data: []byte(`{"description": "{{.Name"}`)}}, want: "Template pass.json"},
+		{
+			name:  "invalid strings placeholder",
+			files: fstest.MapFS{"pass.json": {Data: []byte(`{}`)}, "en.lproj/pass.strings": {Data: []byte(`"a" = "{{if}}";`)}},
+			want:  "Template en.lproj/pass.strings",
+		},
+	}
+
+	for _, tt := range tests {
+		if _, err := LoadTemplateFS(tt.files); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want one mentioning %s", tt.name, err, tt.want)
+		}
+	}
+}
+
+func keys(m map[string][]byte) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+
+	return out
+}
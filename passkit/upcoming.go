@@ -0,0 +1,271 @@
+package passkit
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+type UpcomingPassInformationType string
+
+const (
+	UpcomingPassInformationTypeEvent UpcomingPassInformationType = "event"
+
+	// MaxUpcomingPassInformation is the most upcoming entries this package
+	// lets a pass carry, so that season tickets stay small enough to update
+	// quickly. Wallet itself documents no limit.
+	MaxUpcomingPassInformation = 32
+
+	// MaxRemoteImageScale is the largest scale of a remote image, as for the
+	// @3x image files of a pass.
+	MaxRemoteImageScale = 3
+)
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// UpcomingPassInformation describes a future event of a season or multi-event
+// ticket. Wallet shows the entries in order below the event ticket.
+type UpcomingPassInformation struct {
+	AdditionalInfoFields []PassFieldContent          `json:"additionalInfoFields,omitempty"`
+	BackFields           []PassFieldContent          `json:"backFields,omitempty"`
+	DateInformation      *EventDateInfo              `json:"dateInformation,omitempty"`
+	Identifier           string                      `json:"identifier"`
+	Images               *UpcomingImages             `json:"images,omitempty"`
+	IsActive             bool                        `json:"isActive,omitempty"`
+	Name                 string                      `json:"name"`
+	Semantics            *SemanticTags               `json:"semantics,omitempty"`
+	Type                 UpcomingPassInformationType `json:"type"`
+	URLs                 *UpcomingURLs               `json:"URLs,omitempty"`
+}
+
+func NewUpcomingEvent(identifier, name string) *UpcomingPassInformation {
+	return &UpcomingPassInformation{Identifier: identifier, Name: name, Type: UpcomingPassInformationTypeEvent}
+}
+
+type UpcomingImages struct {
+	HeaderImage *RemoteImage `json:"headerImage,omitempty"`
+	VenueMap    *RemoteImage `json:"venueMap,omitempty"`
+}
+
+// RemoteImage is an image Wallet downloads, or takes from the pass itself
+// when ReuseExisting is set. Upcoming entries have no image files of their
+// own in the archive, so their images are always remote URLs.
+type RemoteImage struct {
+	ReuseExisting bool             `json:"reuseExisting,omitempty"`
+	URLs          []RemoteImageURL `json:"URLs,omitempty"`
+}
+
+type RemoteImageURL struct {
+	Scale  int64  `json:"scale,omitempty"`
+	SHA256 string `json:"SHA256"`
+	Size   int64  `json:"size,omitempty"`
+	URL    string `json:"URL"`
+
+	// Data is the image served at URL. When set, the pass records its
+	// digest and size, so that they always match the hosted file.
+	Data []byte `json:"-"`
+}
+
+func NewRemoteImageURL(url string, scale int64, data []byte) RemoteImageURL {
+	return RemoteImageURL{Scale: scale, URL: url, Data: data}
+}
+
+func (u RemoteImageURL) MarshalJSON() ([]byte, error) {
+	type remoteImageURL RemoteImageURL
+	out := remoteImageURL(u)
+
+	if u.Data != nil {
+		out.SHA256 = digest(u.Data, sha256.New)
+		out.Size = int64(len(u.Data))
+	}
+
+	return json.Marshal(out)
+}
+
+type UpcomingURLs struct {
+	AccessibilityURL         string `json:"accessibilityURL,omitempty"`
+	AddOnURL                 string `json:"addOnURL,omitempty"`
+	BagPolicyURL             string `json:"bagPolicyURL,omitempty"`
+	ContactVenueEmail        string `json:"contactVenueEmail,omitempty"`
+	ContactVenuePhoneNumber  string `json:"contactVenuePhoneNumber,omitempty"`
+	ContactVenueWebsite      string `json:"contactVenueWebsite,omitempty"`
+	DirectionsInformationURL string `json:"directionsInformationURL,omitempty"`
+	MerchandiseURL           string `json:"merchandiseURL,omitempty"`
+	OrderFoodURL             string `json:"orderFoodURL,omitempty"`
+	ParkingInformationURL    string `json:"parkingInformationURL,omitempty"`
+	PurchaseParkingURL       string `json:"purchaseParkingURL,omitempty"`
+	SellURL                  string `json:"sellURL,omitempty"`
+	TransferURL              string `json:"transferURL,omitempty"`
+	TransitInformationURL    string `json:"transitInformationURL,omitempty"`
+}
+
+func (u *UpcomingURLs) links() [][2]string {
+	return [][2]string{
+		{"accessibilityURL", u.AccessibilityURL},
+		{"addOnURL", u.AddOnURL},
+		{"bagPolicyURL", u.BagPolicyURL},
+		{"contactVenueWebsite", u.ContactVenueWebsite},
+		{"directionsInformationURL", u.DirectionsInformationURL},
+		{"merchandiseURL", u.MerchandiseURL},
+		{"orderFoodURL", u.OrderFoodURL},
+		{"parkingInformationURL", u.ParkingInformationURL},
+		{"purchaseParkingURL", u.PurchaseParkingURL},
+		{"sellURL", u.SellURL},
+		{"transferURL", u.TransferURL},
+		{"transitInformationURL", u.TransitInformationURL},
+	}
+}
+
+// IsHTTPSURL reports whether s is an absolute https URL, as Wallet requires
+// for the links and web services of passes and orders.
+func IsHTTPSURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+func (i *RemoteImage) Validate() error {
+	if !i.ReuseExisting && len(i.URLs) == 0 {
+		return errors.New("Image needs URLs unless it reuses an existing image")
+	}
+
+	var errs []error
+	scales := make(map[int64]bool)
+	for _, u := range i.URLs {
+		if !IsHTTPSURL(u.URL) {
+			errs = append(errs, fmt.Errorf("Image URL %q must be an https URL", u.URL))
+		}
+
+		if u.Scale < 0 || u.Scale > MaxRemoteImageScale {
+			errs = append(errs, fmt.Errorf("Image URL %q: scale must be between 1 and %d", u.URL, MaxRemoteImageScale))
+		}
+		if scales[max(u.Scale, 1)] {
+			errs = append(errs, fmt.Errorf("Image URL %q: another URL has scale %d", u.URL, max(u.Scale, 1)))
+		}
+		scales[max(u.Scale, 1)] = true
+
+		if u.Size < 0 {
+			errs = append(errs, fmt.Errorf("Image URL %q: size can not be negative", u.URL))
+		}
+
+		switch {
+		case u.Data == nil && !sha256Pattern.MatchString(u.SHA256):
+			errs = append(errs, fmt.Errorf("Image URL %q: SHA256 must be a lowercase hex digest", u.URL))
+		case u.Data != nil && u.SHA256 != "" && u.SHA256 != digest(u.Data, sha256.New):
+			errs = append(errs, fmt.Errorf("Image URL %q: SHA256 does not match the image data", u.URL))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ImageURLs returns the URLs of every image the entry references, for
+// hosting or checking them ahead of distributing the pass.
+func (u *UpcomingPassInformation) ImageURLs() []string {
+	var urls []string
+	if u.Images == nil {
+		return urls
+	}
+
+	for _, img := range []*RemoteImage{u.Images.HeaderImage, u.Images.VenueMap} {
+		if img == nil {
+			continue
+		}
+
+		for _, i := range img.URLs {
+			urls = append(urls, i.URL)
+		}
+	}
+
+	return urls
+}
+
+func (u *UpcomingPassInformation) Validate() error {
+	var errs []error
+
+	if u.Identifier == "" {
+		errs = append(errs, errors.New("Identifier can not be empty"))
+	}
+
+	if u.Name == "" {
+		errs = append(errs, errors.New("Name can not be empty"))
+	}
+
+	if u.Type != UpcomingPassInformationTypeEvent {
+		errs = append(errs, fmt.Errorf("Unknown type %q", u.Type))
+	}
+
+	for i := range u.AdditionalInfoFields {
+		if err := u.AdditionalInfoFields[i].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Additional info fields: %w", err))
+		}
+	}
+
+	back := &PassFields{BackFields: u.BackFields}
+	if err := back.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if u.Semantics != nil {
+		if err := u.Semantics.Validate(PassStyleEventTicket); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if u.Images != nil {
+		if u.Images.HeaderImage != nil {
+			if err := u.Images.HeaderImage.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("Header image: %w", err))
+			}
+		}
+
+		if u.Images.VenueMap != nil {
+			if err := u.Images.VenueMap.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("Venue map: %w", err))
+			}
+		}
+	}
+
+	if u.URLs != nil {
+		for _, link := range u.URLs.links() {
+			if link[1] != "" && !IsHTTPSURL(link[1]) {
+				errs = append(errs, fmt.Errorf("URL %s %q must be an https URL", link[0], link[1]))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Pass) validateUpcomingPassInformation() error {
+	if len(p.UpcomingPassInformation) == 0 {
+		return nil
+	}
+
+	var errs []error
+
+	if p.EventTicket == nil {
+		errs = append(errs, errors.New("Upcoming pass information is only supported on event tickets"))
+	}
+
+	if len(p.UpcomingPassInformation) > MaxUpcomingPassInformation {
+		errs = append(errs, fmt.Errorf("Upcoming pass information has %d entries, at most %d are allowed", len(p.UpcomingPassInformation), MaxUpcomingPassInformation))
+	}
+
+	seen := make(map[string]int)
+	for i := range p.UpcomingPassInformation {
+		entry := &p.UpcomingPassInformation[i]
+		if err := entry.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Upcoming pass information %d: %w", i, err))
+		}
+
+		if j, ok := seen[entry.Identifier]; ok && entry.Identifier != "" {
+			errs = append(errs, fmt.Errorf("Upcoming pass information %d: identifier %q is already used by entry %d", i, entry.Identifier, j))
+		}
+		seen[entry.Identifier] = i
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+package passkit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUpcomingImageData(t *testing.T) {
+	p, err := NewEventTicketPass("Example", "pass.com.example", "ABCDE12345", "1", "Season ticket")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := NewUpcomingEvent("game-1", "Game 1")
+	entry.Images = &UpcomingImages{HeaderImage: &RemoteImage{URLs: []RemoteImageURL{
+		NewRemoteImageURL("https://example.com/header.png", 1, []byte("header")),
+		NewRemoteImageURL("https://example.com/header@2x.png", 2, []byte("header@2x")),
+	}}}
+	p.UpcomingPassInformation = []UpcomingPassInformation{*entry}
+
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteArchive(&buf, map[string][]byte{"icon.png": []byte("icon")}, fakeSigner{}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ReadArchive(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"scale":1,"SHA256":"` + digest([]byte("header"), sha256.New) + `","size":6,"URL":"https://example.com/header.png"}`
+	if !strings.Contains(string(files["pass.json"]), want) {
+		t.Errorf("pass.json %s does not record the image as %s", files["pass.json"], want)
+	}
+
+	if got := entry.ImageURLs(); len(got) != 2 || got[1] != "https://example.com/header@2x.png" {
+		t.Errorf("image URLs = %q", got)
+	}
+}
+
+func TestValidateUpcomingLimits(t *testing.T) {
+	digestOf := func(s string) string { return digest([]byte(s), sha256.New) }
+
+	tests := []struct {
+		name string
+		urls []RemoteImageURL
+		want string
+	}{
+		{
+			name: "digests given",
+			urls: []RemoteImageURL{{URL: "https://example.com/a.png", SHA256: digestOf("a")}, {URL: "https://example.com/a@3x.png", Scale: 3, SHA256: digestOf("a")}},
+		},
+		{
+			name: "scale too large",
+			urls: []RemoteImageURL{{URL: "https://example.com/a.png", Scale: 4, SHA256: digestOf("a")}},
+			want: `Image URL "https://example.com/a.png": scale must be between 1 and 3`,
+		},
+		{
+			name: "same scale twice",
+			urls: []RemoteImageURL{NewRemoteImageURL("https://example.com/a.png", 0, []byte("a")), NewRemoteImageURL("https://example.com/b.png", 1, []byte("b"))},
+			want: `Image URL "https://example.com/b.png": another URL has scale 1`,
+		},
+		{
+			name: "digest does not match data",
+			urls: []RemoteImageURL{{URL: "https://example.com/a.png", SHA256: digestOf("b"), Data: []byte("a")}},
+			want: `Image URL "https://example.com/a.png": SHA256 does not match the image data`,
+		},
+		{
+			name: "no digest",
+			urls: []RemoteImageURL{{URL: "https://example.com/a.png"}},
+			want: `Image URL "https://example.com/a.png": SHA256 must be a lowercase hex digest`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&RemoteImage{URLs: tt.urls}).Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	p, err := NewEventTicketPass("Example", "pass.com.example", "ABCDE12345", "1", "Season ticket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= MaxUpcomingPassInformation; i++ {
+		p.UpcomingPassInformation = append(p.UpcomingPassInformation, *NewUpcomingEvent(fmt.Sprint("game-", i), "Game"))
+	}
+
+	want := fmt.Sprintf("Upcoming pass information has %d entries, at most %d are allowed", MaxUpcomingPassInformation+1, MaxUpcomingPassInformation)
+	if err := p.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
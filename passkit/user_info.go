@@ -0,0 +1,57 @@
+package passkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MaxUserInfoSize caps the encoded userInfo. It travels with every copy of
+// the pass, so it should hold identifiers rather than records.
+const MaxUserInfoSize = 4 << 10
+
+func validateUserInfo(raw []byte) error {
+	if len(raw) > MaxUserInfoSize {
+		return fmt.Errorf("User info takes %d bytes, at most %d are allowed", len(raw), MaxUserInfoSize)
+	}
+
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		return errors.New("User info must be a JSON object")
+	}
+
+	return nil
+}
+
+func (p *Pass) validateUserInfo() error {
+	if p.UserInfo == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(p.UserInfo)
+	if err != nil {
+		return fmt.Errorf("User info: %w", err)
+	}
+
+	return validateUserInfo(raw)
+}
+
+// UserInfoAs decodes the userInfo of the pass into T, whether it was set with
+// SetUserInfo or decoded from a pass.json.
+func UserInfoAs[T any](p *Pass) (T, error) {
+	var info T
+	if p.UserInfo == nil {
+		return info, errors.New("User info is not set")
+	}
+
+	raw, err := json.Marshal(p.UserInfo)
+	if err != nil {
+		return info, err
+	}
+
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return info, fmt.Errorf("User info: %w", err)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,161 @@
+package passkit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Style returns the style of the pass, or an empty string if none is set.
+func (p *Pass) Style() PassStyle {
+	switch {
+	case p.BoardingPass != nil:
+		return PassStyleBoardingPass
+	case p.Coupon != nil:
+		return PassStyleCoupon
+	case p.EventTicket != nil:
+		return PassStyleEventTicket
+	case p.Generic != nil:
+		return PassStyleGeneric
+	case p.StoreCard != nil:
+		return PassStyleStoreCard
+	}
+
+	return ""
+}
+
+func (p *Pass) passFields() *PassFields {
+	switch {
+	case p.BoardingPass != nil:
+		return p.BoardingPass.PassFields
+	case p.Coupon != nil:
+		return p.Coupon.PassFields
+	case p.EventTicket != nil:
+		return p.EventTicket.PassFields
+	case p.Generic != nil:
+		return p.Generic.PassFields
+	case p.StoreCard != nil:
+		return p.StoreCard.PassFields
+	}
+
+	return nil
+}
+
+func isValidTransitType(t TransitType) bool {
+	switch t {
+	case TransitTypeAir, TransitTypeBoat, TransitTypeBus, TransitTypeGeneric, TransitTypeTrain:
+		return true
+	}
+
+	return false
+}
+
+// validateRequired checks the keys every pass.json must have.
+func (p *Pass) validateRequired() error {
+	var errs []error
+
+	if p.FormatVersion != 1 {
+		errs = append(errs, errors.New("Format version can only be 1"))
+	}
+
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"Description", p.Description},
+		{"Organization name", p.OrganizationName},
+		{"Pass type identifier", p.PassTypeIdentifier},
+		{"Serial number", p.SerialNumber},
+		{"Team identifier", p.TeamIdentifier},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			errs = append(errs, fmt.Errorf("%s can not be empty", r.name))
+		}
+	}
+
+	styles := 0
+	for _, set := range []bool{p.BoardingPass != nil, p.Coupon != nil, p.EventTicket != nil, p.Generic != nil, p.StoreCard != nil} {
+		if set {
+			styles++
+		}
+	}
+
+	switch {
+	case styles == 0:
+		errs = append(errs, errors.New("Pass style can not be empty"))
+	case styles > 1:
+		errs = append(errs, errors.New("Pass can only have one style"))
+	}
+
+	if p.BoardingPass != nil && !isValidTransitType(p.BoardingPass.TransitType) {
+		errs = append(errs, fmt.Errorf("Unknown transit type %q", p.BoardingPass.TransitType))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate runs every check the package knows about and returns all problems
+// found, joined into a single error.
+func (p *Pass) Validate() error {
+	var errs []error
+
+	if err := p.validateRequired(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i := range p.Barcodes {
+		if err := p.Barcodes[i].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Barcode %d: %w", i, err))
+		}
+	}
+
+	if p.Barcode != nil {
+		if err := p.Barcode.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Legacy barcode: %w", err))
+		}
+	}
+
+	if fields := p.passFields(); fields != nil {
+		if err := fields.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.EventTicket != nil {
+		for i := range p.EventTicket.AdditionalInfoFields {
+			if err := p.EventTicket.AdditionalInfoFields[i].Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("Additional info fields: %w", err))
+			}
+		}
+	}
+
+	if err := p.validateRelevantDates(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := p.validateStyleSchemes(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := p.validateUserInfo(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := p.validateUpcomingPassInformation(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if p.Semantics != nil {
+		if err := p.Semantics.Validate(p.Style()); err != nil {
+			errs = append(errs, err)
+		}
+
+		if p.BoardingPass != nil {
+			if err := p.Semantics.ValidateTransitType(p.BoardingPass.TransitType); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,90 @@
+package passkit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/clevtech/apple-wallet-pass/internal/webservice"
+)
+
+// ErrNotFound is returned by a Store for unknown passes and registrations.
+var ErrNotFound = webservice.ErrNotFound
+
+// Store is the persistence behind the pass web service.
+type Store interface {
+	// AuthenticationToken returns the authenticationToken of the pass.
+	AuthenticationToken(ctx context.Context, passTypeIdentifier, serialNumber string) (string, error)
+
+	// Register records that a device wants updates for a pass and reports
+	// whether the registration is new.
+	Register(ctx context.Context, deviceLibraryIdentifier, pushToken, passTypeIdentifier, serialNumber string) (bool, error)
+
+	Unregister(ctx context.Context, deviceLibraryIdentifier, passTypeIdentifier, serialNumber string) error
+
+	// UpdatedPasses returns the serial numbers of the passes registered to a
+	// device that changed after updatedSince, an opaque tag from an earlier
+	// call that is empty on the first one, and the tag to use next.
+	UpdatedPasses(ctx context.Context, deviceLibraryIdentifier, passTypeIdentifier, updatedSince string) ([]string, string, error)
+
+	// Pass returns the signed .pkpass archive and when it last changed.
+	Pass(ctx context.Context, passTypeIdentifier, serialNumber string) ([]byte, time.Time, error)
+}
+
+// Handler serves the pass web service under the webServiceURL of the passes,
+// which must be stripped before it reaches the handler.
+type Handler struct {
+	Store Store
+
+	// Log receives the messages devices post about errors; it may be nil.
+	Log func(messages []string)
+
+	handler http.Handler
+}
+
+func NewHandler(store Store) *Handler {
+	h := &Handler{Store: store}
+	h.handler = webservice.NewHandler(webservice.Protocol{
+		Scheme:      "ApplePass",
+		Items:       "passes",
+		ContentType: PassContentType,
+		SinceParam:  "passesUpdatedSince",
+		IDsKey:      "serialNumbers",
+		TagKey:      "lastUpdated",
+	}, passStore{h}, func(messages []string) {
+		if h.Log != nil {
+			h.Log(messages)
+		}
+	})
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+// passStore serves the requests from the Store the handler holds at the time.
+type passStore struct {
+	h *Handler
+}
+
+func (s passStore) AuthenticationToken(ctx context.Context, typeID, id string) (string, error) {
+	return s.h.Store.AuthenticationToken(ctx, typeID, id)
+}
+
+func (s passStore) Register(ctx context.Context, device, pushToken, typeID, id string) (bool, error) {
+	return s.h.Store.Register(ctx, device, pushToken, typeID, id)
+}
+
+func (s passStore) Unregister(ctx context.Context, device, typeID, id string) error {
+	return s.h.Store.Unregister(ctx, device, typeID, id)
+}
+
+func (s passStore) Updated(ctx context.Context, device, typeID, since string) ([]string, string, error) {
+	return s.h.Store.UpdatedPasses(ctx, device, typeID, since)
+}
+
+func (s passStore) Item(ctx context.Context, typeID, id string) ([]byte, time.Time, error) {
+	return s.h.Store.Pass(ctx, typeID, id)
+}
@@ -0,0 +1,68 @@
+package passkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tokenStore holds one pass with the given authentication token.
+type tokenStore struct {
+	token string
+}
+
+func (s tokenStore) AuthenticationToken(ctx context.Context, passTypeIdentifier, serialNumber string) (string, error) {
+	return s.token, nil
+}
+
+func (s tokenStore) Register(ctx context.Context, deviceLibraryIdentifier, pushToken, passTypeIdentifier, serialNumber string) (bool, error) {
+	return true, nil
+}
+
+func (s tokenStore) Unregister(ctx context.Context, deviceLibraryIdentifier, passTypeIdentifier, serialNumber string) error {
+	return nil
+}
+
+func (s tokenStore) UpdatedPasses(ctx context.Context, deviceLibraryIdentifier, passTypeIdentifier, updatedSince string) ([]string, string, error) {
+	return nil, "", nil
+}
+
+func (s tokenStore) Pass(ctx context.Context, passTypeIdentifier, serialNumber string) ([]byte, time.Time, error) {
+	return []byte("archive"), time.Now(), nil
+}
+
+func TestHandlerAuthorization(t *testing.T) {
+	requests := []struct{ method, path, body string }{
+		{"GET", "/v1/passes/pass.com.example/1", ""},
+		{"POST", "/v1/devices/d1/registrations/pass.com.example/1", `{"pushToken":"t"}`},
+		{"DELETE", "/v1/devices/d1/registrations/pass.com.example/1", ""},
+	}
+
+	tests := []struct {
+		name, token, auth string
+		status            int
+	}{
+		{name: "no token", token: "", auth: "ApplePass ", status: http.StatusUnauthorized},
+		{name: "empty given token", token: "secret-token-0123", auth: "ApplePass ", status: http.StatusUnauthorized},
+		{name: "order scheme", token: "secret-token-0123", auth: "AppleOrder secret-token-0123", status: http.StatusUnauthorized},
+		{name: "matching token", token: "secret-token-0123", auth: "ApplePass secret-token-0123"},
+	}
+
+	for _, tt := range tests {
+		h := NewHandler(tokenStore{token: tt.token})
+		for _, req := range requests {
+			r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+			r.Header.Set("Authorization", tt.auth)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if unauthorized := w.Code == http.StatusUnauthorized; unauthorized != (tt.status == http.StatusUnauthorized) {
+				t.Errorf("%s: %s %s: status %d", tt.name, req.method, req.path, w.Code)
+			}
+		}
+	}
+}
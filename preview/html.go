@@ -0,0 +1,179 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"io"
+
+	"github.com/clevtech/apple-wallet-pass/barcode"
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+var transitSymbols = map[passkit.TransitType]string{
+	passkit.TransitTypeAir:     "✈",
+	passkit.TransitTypeBoat:    "⛴",
+	passkit.TransitTypeBus:     "\U0001f68c",
+	passkit.TransitTypeGeneric: "→",
+	passkit.TransitTypeTrain:   "\U0001f686",
+}
+
+var textAligns = map[passkit.TextAlignment]string{
+	passkit.TextAlignmentLeft:   "left",
+	passkit.TextAlignmentCenter: "center",
+	passkit.TextAlignmentRight:  "right",
+}
+
+type htmlField struct {
+	Label string
+	Value string
+	Align string
+
+	// Before is the transit symbol shown between boarding pass primary
+	// fields.
+	Before string
+}
+
+type htmlView struct {
+	Title      string
+	Style      string
+	PassCSS    template.CSS
+	LabelCSS   template.CSS
+	LogoText   string
+	Logo       template.URL
+	Strip      template.URL
+	Thumbnail  template.URL
+	Background template.URL
+	Footer     template.URL
+	Header     []htmlField
+	Primary    []htmlField
+	Rows       [][]htmlField
+	Barcode    template.URL
+	Voided     bool
+	Back       []htmlField
+}
+
+var htmlTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; padding: 24px; display: flex; flex-wrap: wrap; gap: 24px; align-items: flex-start; background: #e5e5ea; font-family: -apple-system, "Helvetica Neue", Arial, sans-serif; }
+.pass { position: relative; width: 320px; border-radius: 12px; overflow: hidden; box-shadow: 0 2px 10px rgba(0, 0, 0, .3); }
+.pass > * { position: relative; }
+.pass > .background { position: absolute; inset: 0; background-size: cover; background-position: center; filter: blur(8px); opacity: .6; }
+.top { display: flex; align-items: center; gap: 8px; padding: 8px 12px; min-height: 34px; }
+.logo { max-width: 160px; max-height: 50px; }
+.logotext { flex: 1; font-size: 17px; font-weight: 600; }
+.fields { display: flex; gap: 12px; padding: 6px 12px; }
+.top .fields { padding: 0; }
+.field { flex: 1; min-width: 0; }
+.label { font-size: 10px; font-weight: 600; text-transform: uppercase; }
+.value { font-size: 15px; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+.primary .value { font-size: 28px; font-weight: 300; }
+.boardingPass .primary .value { font-size: 40px; }
+.transit { align-self: center; font-size: 28px; }
+.strip { background-size: cover; background-position: center; min-height: 98px; display: flex; align-items: flex-end; }
+.thumbnail { max-width: 90px; max-height: 90px; }
+.footer { display: block; max-width: 286px; max-height: 15px; margin: 6px auto; }
+.barcode { padding: 12px; text-align: center; }
+.barcode img { max-width: 100%; background: #fff; border-radius: 4px; padding: 6px; }
+.void { padding: 16px; text-align: center; font-weight: 600; }
+.back { background: #f2f2f7; color: #000; padding: 12px; }
+.back .entry { padding: 8px 0; border-bottom: 1px solid #d1d1d6; }
+.front .label { {{.LabelCSS}} }
+.back .label { color: #6e6e73; }
+.back .value { white-space: pre-wrap; overflow-wrap: anywhere; }
+</style>
+</head>
+<body>
+{{define "fields"}}{{range .}}{{if .Before}}<div class="transit">{{.Before}}</div>{{end}}<div class="field"{{with .Align}} style="text-align: {{.}}"{{end}}><div class="label">{{.Label}}</div><div class="value">{{.Value}}</div></div>{{end}}{{end}}
+<div class="pass front {{.Style}}" style="{{.PassCSS}}">
+{{if .Background}}<div class="background" style="background-image: url('{{.Background}}')"></div>{{end}}
+<div class="top">
+{{if .Logo}}<img class="logo" src="{{.Logo}}" alt="">{{end}}
+<div class="logotext">{{.LogoText}}</div>
+{{if .Header}}<div class="fields">{{template "fields" .Header}}</div>{{end}}
+</div>
+{{if .Strip}}<div class="strip" style="background-image: url('{{.Strip}}')">{{end}}
+<div class="fields primary">
+{{template "fields" .Primary}}
+{{if .Thumbnail}}<img class="thumbnail" src="{{.Thumbnail}}" alt="">{{end}}
+</div>
+{{if .Strip}}</div>{{end}}
+{{range .Rows}}<div class="fields">{{template "fields" .}}</div>
+{{end}}
+{{if .Footer}}<img class="footer" src="{{.Footer}}" alt="">{{end}}
+{{if .Voided}}<div class="void">This pass is void</div>{{else if .Barcode}}<div class="barcode"><img src="{{.Barcode}}" alt=""></div>{{end}}
+</div>
+<div class="pass back">
+{{range .Back}}<div class="entry"><div class="label">{{.Label}}</div><div class="value">{{.Value}}</div></div>
+{{else}}<div class="entry"><div class="value">No back fields</div></div>
+{{end}}</div>
+</body>
+</html>
+`))
+
+func dataURL(png []byte) template.URL {
+	if png == nil {
+		return ""
+	}
+
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png))
+}
+
+func htmlFields(fields []field) []htmlField {
+	out := make([]htmlField, 0, len(fields))
+	for _, f := range fields {
+		out = append(out, htmlField{Label: f.label, Value: f.value, Align: textAligns[f.align]})
+	}
+
+	return out
+}
+
+// HTML writes a standalone page showing the front and back of the pass, with
+// the images and barcode inlined.
+func (pv *Preview) HTML(w io.Writer) error {
+	m, err := pv.model()
+	if err != nil {
+		return err
+	}
+
+	v := htmlView{
+		Title:      pv.Pass.Description,
+		Style:      string(m.style),
+		PassCSS:    template.CSS("background-color: " + m.background.Hex() + "; color: " + m.foreground.Hex()),
+		LabelCSS:   template.CSS("color: " + m.label.Hex()),
+		LogoText:   m.logoText,
+		Logo:       dataURL(m.images[passkit.ImageRoleLogo]),
+		Strip:      dataURL(m.images[passkit.ImageRoleStrip]),
+		Thumbnail:  dataURL(m.images[passkit.ImageRoleThumbnail]),
+		Background: dataURL(m.images[passkit.ImageRoleBackground]),
+		Footer:     dataURL(m.images[passkit.ImageRoleFooter]),
+		Header:     htmlFields(m.header),
+		Primary:    htmlFields(m.primary),
+		Voided:     m.voided,
+		Back:       htmlFields(m.back),
+	}
+
+	for _, row := range m.rows {
+		v.Rows = append(v.Rows, htmlFields(row))
+	}
+
+	if m.style == passkit.PassStyleBoardingPass {
+		for i := 1; i < len(v.Primary); i++ {
+			v.Primary[i].Before = transitSymbols[m.transitType]
+		}
+	}
+
+	if m.barcode != nil {
+		var buf bytes.Buffer
+		if err := barcode.WritePNG(&buf, *m.barcode, 2); err != nil {
+			return err
+		}
+		v.Barcode = dataURL(buf.Bytes())
+	}
+
+	return htmlTemplate.Execute(w, v)
+}
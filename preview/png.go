@@ -0,0 +1,341 @@
+package preview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/clevtech/apple-wallet-pass/barcode"
+	"github.com/clevtech/apple-wallet-pass/internal/font"
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+// The PNG is drawn at twice the 320 point width of a pass on screen.
+const (
+	imageWidth = 640
+	padding    = 24
+	gap        = 12
+
+	labelScale   = 2
+	valueScale   = 3
+	primaryScale = 5
+)
+
+// canvas draws the pass top to bottom. With a nil img it only measures, so
+// the height is known before the background is painted.
+type canvas struct {
+	img *image.RGBA
+	y   int
+}
+
+func rgba(c passkit.Color) color.RGBA {
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: 0xff}
+}
+
+func decodePNG(data []byte) image.Image {
+	if data == nil {
+		return nil
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+
+	return img
+}
+
+// scaled draws src resized with nearest neighbour sampling into r.
+func (c *canvas) scaled(r image.Rectangle, src image.Image, sr image.Rectangle) {
+	if c.img == nil || r.Empty() || sr.Empty() {
+		return
+	}
+
+	tmp := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			tmp.Set(x, y, src.At(sr.Min.X+x*sr.Dx()/r.Dx(), sr.Min.Y+y*sr.Dy()/r.Dy()))
+		}
+	}
+	draw.Draw(c.img, r, tmp, image.Point{}, draw.Over)
+}
+
+// contain draws src as large as fits in box, keeping its aspect ratio and
+// aligning it to the left, center or right as align is -1, 0 or 1. It
+// returns the rectangle drawn to.
+func (c *canvas) contain(box image.Rectangle, src image.Image, align int) image.Rectangle {
+	sb := src.Bounds()
+	w, h := box.Dx(), sb.Dy()*box.Dx()/sb.Dx()
+	if h > box.Dy() {
+		w, h = sb.Dx()*box.Dy()/sb.Dy(), box.Dy()
+	}
+
+	x := box.Min.X
+	switch align {
+	case 0:
+		x += (box.Dx() - w) / 2
+	case 1:
+		x = box.Max.X - w
+	}
+
+	r := image.Rect(x, box.Min.Y+(box.Dy()-h)/2, x+w, box.Min.Y+(box.Dy()-h)/2+h)
+	c.scaled(r, src, sb)
+
+	return r
+}
+
+// cover fills box with src, cropping its center to keep the aspect ratio.
+func (c *canvas) cover(box image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	crop := sb
+	if sb.Dx()*box.Dy() > sb.Dy()*box.Dx() {
+		w := sb.Dy() * box.Dx() / box.Dy()
+		crop.Min.X += (sb.Dx() - w) / 2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := sb.Dx() * box.Dy() / box.Dx()
+		crop.Min.Y += (sb.Dy() - h) / 2
+		crop.Max.Y = crop.Min.Y + h
+	}
+
+	c.scaled(box, src, crop)
+}
+
+func (c *canvas) fill(r image.Rectangle, col color.Color) {
+	if c.img != nil {
+		draw.Draw(c.img, r, image.NewUniform(col), image.Point{}, draw.Over)
+	}
+}
+
+// text draws s fitted to width, cut short with ".." if it is too long.
+func (c *canvas) text(x, y, width int, s string, scale int, col color.Color, align passkit.TextAlignment) {
+	if limit := width / (font.Width * scale); len([]rune(s)) > limit {
+		if limit < 2 {
+			return
+		}
+		s = string([]rune(s)[:limit-2]) + ".."
+	}
+
+	switch align {
+	case passkit.TextAlignmentCenter:
+		x += (width - font.TextWidth(s, scale)) / 2
+	case passkit.TextAlignmentRight:
+		x += width - font.TextWidth(s, scale)
+	}
+
+	if c.img != nil {
+		font.Draw(c.img, x, y, s, scale, col)
+	}
+}
+
+// fieldHeight is the height of a label above a value.
+func fieldHeight(scale int) int {
+	return font.Height*labelScale + font.Height*scale
+}
+
+// fields draws a row of fields sharing the width equally.
+func (c *canvas) fields(x, width int, fields []field, scale int, m *model, defaultAlign passkit.TextAlignment) {
+	if len(fields) == 0 {
+		return
+	}
+
+	each := (width - gap*(len(fields)-1)) / len(fields)
+	for i, f := range fields {
+		align := f.align
+		if align == "" || align == passkit.TextAlignmentNatural {
+			align = defaultAlign
+		}
+
+		fx := x + i*(each+gap)
+		c.text(fx, c.y, each, strings.ToUpper(f.label), labelScale, rgba(m.label), align)
+		c.text(fx, c.y+font.Height*labelScale, each, f.value, scale, rgba(m.foreground), align)
+	}
+	c.y += fieldHeight(scale)
+}
+
+func (c *canvas) front(m *model) {
+	fg := rgba(m.foreground)
+	c.y = padding
+
+	// Logo, logo text and header fields share the top row.
+	top := image.Rect(padding, c.y, imageWidth-padding, c.y+max(100, fieldHeight(valueScale)))
+	x := top.Min.X
+	if logo := decodePNG(m.images[passkit.ImageRoleLogo]); logo != nil {
+		x = c.contain(image.Rect(x, top.Min.Y, x+320, top.Max.Y), logo, -1).Max.X + gap
+	}
+
+	headerWidth := 0
+	if len(m.header) > 0 {
+		headerWidth = min(top.Max.X-x, len(m.header)*200)
+		saved := c.y
+		c.y = top.Min.Y + (top.Dy()-fieldHeight(valueScale))/2
+		c.fields(top.Max.X-headerWidth, headerWidth, m.header, valueScale, m, passkit.TextAlignmentRight)
+		c.y = saved
+	}
+
+	if m.logoText != "" {
+		c.text(x, top.Min.Y+(top.Dy()-font.Height*valueScale)/2, top.Max.X-headerWidth-gap-x, m.logoText, valueScale, fg, passkit.TextAlignmentLeft)
+	}
+	c.y = top.Max.Y + gap
+
+	// Primary fields, over the strip or beside the thumbnail.
+	width := imageWidth - 2*padding
+	if strip := decodePNG(m.images[passkit.ImageRoleStrip]); strip != nil {
+		box := image.Rect(0, c.y, imageWidth, c.y+246)
+		c.cover(box, strip)
+		c.y = box.Max.Y - fieldHeight(primaryScale) - gap
+		c.fields(padding, width, m.primary, primaryScale, m, passkit.TextAlignmentLeft)
+		c.y = box.Max.Y + gap
+	} else if m.style == passkit.PassStyleBoardingPass && len(m.primary) == 2 {
+		symbol := "->"
+		c.fields(padding, width, m.primary[:1], primaryScale, m, passkit.TextAlignmentLeft)
+		c.y -= fieldHeight(primaryScale)
+		c.text(padding, c.y+font.Height*labelScale, width, symbol, primaryScale, fg, passkit.TextAlignmentCenter)
+		c.fields(padding, width, m.primary[1:], primaryScale, m, passkit.TextAlignmentRight)
+		c.y += gap
+	} else {
+		start := c.y
+		if thumb := decodePNG(m.images[passkit.ImageRoleThumbnail]); thumb != nil {
+			c.contain(image.Rect(imageWidth-padding-180, c.y, imageWidth-padding, c.y+180), thumb, 1)
+			width -= 180 + gap
+		}
+		c.fields(padding, width, m.primary, primaryScale, m, passkit.TextAlignmentLeft)
+		if m.images[passkit.ImageRoleThumbnail] != nil {
+			c.y = max(c.y, start+180)
+		}
+		c.y += gap
+	}
+
+	for _, row := range m.rows {
+		c.fields(padding, imageWidth-2*padding, row, valueScale, m, passkit.TextAlignmentLeft)
+		c.y += gap
+	}
+
+	if footer := decodePNG(m.images[passkit.ImageRoleFooter]); footer != nil {
+		c.contain(image.Rect(padding, c.y, imageWidth-padding, c.y+30), footer, 0)
+		c.y += 30 + gap
+	}
+
+	switch {
+	case m.voided:
+		c.y += gap
+		c.text(padding, c.y, imageWidth-2*padding, "This pass is void", valueScale, fg, passkit.TextAlignmentCenter)
+		c.y += font.Height*valueScale + gap
+	case m.barcode != nil:
+		code, err := barcode.Image(*m.barcode, 4)
+		if err != nil {
+			break
+		}
+
+		box := image.Rect(padding, c.y+gap, imageWidth-padding, c.y+gap+min(code.Bounds().Dy(), 400))
+		c.y = c.contain(box, code, 0).Max.Y + gap
+	}
+	c.y += padding - gap
+}
+
+// back draws the back fields as Wallet's grey list of labelled values.
+func (c *canvas) back(m *model) {
+	label := color.RGBA{R: 0x6e, G: 0x6e, B: 0x73, A: 0xff}
+	line := color.RGBA{R: 0xd1, G: 0xd1, B: 0xd6, A: 0xff}
+	width := imageWidth - 2*padding
+	perLine := width / (font.Width * labelScale)
+
+	c.y = padding
+	if len(m.back) == 0 {
+		c.text(padding, c.y, width, "No back fields", labelScale, color.Black, passkit.TextAlignmentLeft)
+		c.y += font.Height*labelScale + padding
+		return
+	}
+
+	for _, f := range m.back {
+		c.text(padding, c.y, width, strings.ToUpper(f.label), labelScale, label, passkit.TextAlignmentLeft)
+		c.y += font.Height*labelScale + 4
+
+		for _, l := range wrap(f.value, perLine) {
+			c.text(padding, c.y, width, l, labelScale, color.Black, passkit.TextAlignmentLeft)
+			c.y += font.Height * labelScale
+		}
+
+		c.y += gap
+		c.fill(image.Rect(padding, c.y, imageWidth-padding, c.y+1), line)
+		c.y += gap
+	}
+	c.y += padding - gap
+}
+
+// wrap breaks s into lines of at most width runes, at spaces where it can.
+func wrap(s string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for len([]rune(word)) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, string([]rune(word)[:width]))
+				word = string([]rune(word)[width:])
+			}
+
+			switch {
+			case line == "":
+				line = word
+			case len([]rune(line))+1+len([]rune(word)) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// Image renders one side of the pass. The text uses a small bitmap font
+// that only covers ASCII, so other characters show as '?'.
+func (pv *Preview) Image(side Side) (image.Image, error) {
+	m, err := pv.model()
+	if err != nil {
+		return nil, err
+	}
+
+	render := (*canvas).front
+	background := rgba(m.background)
+	if side == Back {
+		render = (*canvas).back
+		background = color.RGBA{R: 0xf2, G: 0xf2, B: 0xf7, A: 0xff}
+	}
+
+	measure := &canvas{}
+	render(measure, m)
+
+	c := &canvas{img: image.NewRGBA(image.Rect(0, 0, imageWidth, measure.y))}
+	c.fill(c.img.Bounds(), background)
+
+	if side == Front {
+		if bg := decodePNG(m.images[passkit.ImageRoleBackground]); bg != nil {
+			// Wallet blurs the background; dimming keeps the text readable.
+			c.cover(c.img.Bounds(), bg)
+			c.fill(c.img.Bounds(), color.RGBA{R: background.R / 2, G: background.G / 2, B: background.B / 2, A: 0x80})
+		}
+	}
+	render(c, m)
+
+	return c.img, nil
+}
+
+func (pv *Preview) PNG(w io.Writer, side Side) error {
+	img, err := pv.Image(side)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
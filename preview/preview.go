@@ -0,0 +1,272 @@
+// Package preview renders an approximation of how Wallet shows a pass, front
+// and back, as static HTML or a PNG image. It lays out the fields, images,
+// colors and barcode by pass style but does not try to match Wallet to the
+// pixel.
+package preview
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+// Side is a side of the pass.
+type Side int
+
+const (
+	Front Side = iota
+	Back
+)
+
+// Preview is a pass together with the files of its bundle, such as logo.png
+// or en.lproj/pass.strings, keyed by their path in the archive.
+type Preview struct {
+	Pass  *passkit.Pass
+	Files map[string][]byte
+
+	// Language picks the xx.lproj folder whose strings and images are used,
+	// such as "en". It is empty for none.
+	Language string
+}
+
+func New(p *passkit.Pass, files map[string][]byte) *Preview {
+	return &Preview{Pass: p, Files: files}
+}
+
+// styleImages lists the images each pass style shows.
+var styleImages = map[passkit.PassStyle][]passkit.ImageRole{
+	passkit.PassStyleBoardingPass: {passkit.ImageRoleLogo, passkit.ImageRoleFooter},
+	passkit.PassStyleCoupon:       {passkit.ImageRoleLogo, passkit.ImageRoleStrip},
+	passkit.PassStyleEventTicket:  {passkit.ImageRoleLogo, passkit.ImageRoleStrip, passkit.ImageRoleBackground, passkit.ImageRoleThumbnail},
+	passkit.PassStyleGeneric:      {passkit.ImageRoleLogo, passkit.ImageRoleThumbnail},
+	passkit.PassStyleStoreCard:    {passkit.ImageRoleLogo, passkit.ImageRoleStrip},
+}
+
+var (
+	dateLayouts = map[passkit.DateStyle]string{
+		passkit.DateStyleShort:  "1/2/06",
+		passkit.DateStyleMedium: "Jan 2, 2006",
+		passkit.DateStyleLong:   "January 2, 2006",
+		passkit.DateStyleFull:   "Monday, January 2, 2006",
+	}
+
+	timeLayouts = map[passkit.TimeStyle]string{
+		passkit.TimeStyleShort:  "3:04 PM",
+		passkit.TimeStyleMedium: "3:04:05 PM",
+		passkit.TimeStyleLong:   "3:04:05 PM MST",
+		passkit.TimeStyleFull:   "3:04:05 PM MST",
+	}
+)
+
+type field struct {
+	label string
+	value string
+	align passkit.TextAlignment
+}
+
+// model is the laid out content of a pass, shared by the renderers.
+type model struct {
+	style       passkit.PassStyle
+	transitType passkit.TransitType
+	background  passkit.Color
+	foreground  passkit.Color
+	label       passkit.Color
+	logoText    string
+	images      map[passkit.ImageRole][]byte
+	header      []field
+	primary     []field
+	rows        [][]field
+	back        []field
+	barcode     *passkit.Barcodes
+	voided      bool
+}
+
+func (pv *Preview) strings() map[string]string {
+	if pv.Language == "" {
+		return nil
+	}
+
+	data, ok := pv.Files[pv.Language+".lproj/pass.strings"]
+	if !ok {
+		return nil
+	}
+
+	f, err := passkit.ParseStringsFile(data)
+	if err != nil {
+		return nil
+	}
+
+	return f.Values
+}
+
+// image returns the sharpest PNG for role, preferring the localized one.
+func (pv *Preview) image(role passkit.ImageRole) []byte {
+	var dirs []string
+	if pv.Language != "" {
+		dirs = append(dirs, pv.Language+".lproj/")
+	}
+	dirs = append(dirs, "")
+
+	for _, dir := range dirs {
+		for _, suffix := range []string{"@3x", "@2x", ""} {
+			if data, ok := pv.Files[dir+string(role)+suffix+".png"]; ok {
+				return data
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatNumber(f passkit.PassFieldContent, n float64) string {
+	if f.CurrencyCode != "" {
+		return fmt.Sprintf("%s %.2f", f.CurrencyCode, n)
+	}
+
+	switch f.NumberStyle {
+	case passkit.NumberStylePercent:
+		return strconv.FormatFloat(n*100, 'f', -1, 64) + "%"
+	case passkit.NumberStyleScientific:
+		return strconv.FormatFloat(n, 'E', -1, 64)
+	}
+
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// formatValue formats a field value roughly the way Wallet does in US English.
+// Dates keep the offset they were given in, as the device time zone is not
+// known.
+func formatValue(f passkit.PassFieldContent, localize func(string) string) string {
+	switch {
+	case f.Value == nil:
+		return ""
+	case f.Value.IsNumber():
+		return formatNumber(f, f.Value.Number())
+	case f.Value.IsDate():
+		var parts []string
+		if layout, ok := dateLayouts[f.DateStyle]; ok {
+			parts = append(parts, f.Value.Date().Format(layout))
+		}
+		if layout, ok := timeLayouts[f.TimeStyle]; ok {
+			parts = append(parts, f.Value.Date().Format(layout))
+		}
+
+		if len(parts) == 0 {
+			return f.Value.String()
+		}
+
+		return strings.Join(parts, " at ")
+	}
+
+	return localize(f.Value.String())
+}
+
+func (pv *Preview) model() (*model, error) {
+	p := pv.Pass
+	style := p.Style()
+	if style == "" {
+		return nil, errors.New("Pass style can not be empty")
+	}
+
+	values := pv.strings()
+	localize := func(s string) string {
+		if v, ok := values[s]; ok {
+			return v
+		}
+
+		return s
+	}
+
+	fields := func(list []passkit.PassFieldContent) []field {
+		out := make([]field, 0, len(list))
+		for _, f := range list {
+			out = append(out, field{label: localize(f.Label), value: formatValue(f, localize), align: f.TextAlignment})
+		}
+
+		return out
+	}
+
+	m := &model{
+		style:      style,
+		background: passkit.Color{R: 255, G: 255, B: 255},
+		logoText:   localize(p.LogoText),
+		images:     make(map[passkit.ImageRole][]byte),
+		voided:     p.Voided,
+	}
+
+	if p.BackgroundColor != nil {
+		m.background = *p.BackgroundColor
+	}
+	if p.ForegroundColor != nil {
+		m.foreground = *p.ForegroundColor
+	}
+	m.label = m.foreground
+	if p.LabelColor != nil {
+		m.label = *p.LabelColor
+	}
+
+	for _, role := range styleImages[style] {
+		if data := pv.image(role); data != nil {
+			m.images[role] = data
+		}
+	}
+
+	// Event tickets show either a strip or a background and thumbnail.
+	if m.images[passkit.ImageRoleStrip] != nil {
+		delete(m.images, passkit.ImageRoleBackground)
+		delete(m.images, passkit.ImageRoleThumbnail)
+	}
+
+	var pf *passkit.PassFields
+	switch style {
+	case passkit.PassStyleBoardingPass:
+		pf = p.BoardingPass.PassFields
+		m.transitType = p.BoardingPass.TransitType
+	case passkit.PassStyleCoupon:
+		pf = p.Coupon.PassFields
+	case passkit.PassStyleEventTicket:
+		pf = p.EventTicket.PassFields
+	case passkit.PassStyleGeneric:
+		pf = p.Generic.PassFields
+	case passkit.PassStyleStoreCard:
+		pf = p.StoreCard.PassFields
+	}
+
+	if pf != nil {
+		m.header = fields(pf.HeaderFields)
+		m.primary = fields(pf.PrimaryFields)
+		m.back = fields(pf.BackFields)
+		if style == passkit.PassStyleEventTicket {
+			m.back = append(m.back, fields(p.EventTicket.AdditionalInfoFields)...)
+		}
+
+		secondary, auxiliary := fields(pf.SecondaryFields), fields(pf.AuxiliaryFields)
+		if style == passkit.PassStyleCoupon || style == passkit.PassStyleStoreCard {
+			// Coupons and store cards put both groups on one row.
+			secondary, auxiliary = append(secondary, auxiliary...), nil
+		}
+
+		for _, row := range [][]field{secondary, auxiliary} {
+			if len(row) > 0 {
+				m.rows = append(m.rows, row)
+			}
+		}
+	}
+
+	if len(p.Barcodes) > 0 {
+		m.barcode = &p.Barcodes[0]
+	} else if p.Barcode != nil {
+		m.barcode = p.Barcode
+	}
+
+	if m.barcode != nil && m.barcode.AltText != "" {
+		b := *m.barcode
+		b.AltText = localize(b.AltText)
+		m.barcode = &b
+	}
+
+	return m, nil
+}
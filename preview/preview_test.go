@@ -0,0 +1,166 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/clevtech/apple-wallet-pass/passkit"
+)
+
+func testPass(t *testing.T, style string) *passkit.Pass {
+	t.Helper()
+
+	data := `{
+		"formatVersion": 1,
+		"passTypeIdentifier": "pass.com.example",
+		"serialNumber": "1",
+		"teamIdentifier": "ABCDE12345",
+		"organizationName": "Example",
+		"description": "Preview",
+		"logoText": "Example Co",
+		"barcodes": [{"format": "PKBarcodeFormatQR", "message": "1", "messageEncoding": "iso-8859-1"}],
+		"` + style + `": ` + style + `Fields
+	}`
+	fields := `{
+		"headerFields": [{"key": "h", "label": "Header", "value": "H1"}],
+		"primaryFields": [{"key": "from", "label": "From", "value": "SFO"}, {"key": "to", "label": "To", "value": "JFK"}],
+		"secondaryFields": [{"key": "s", "label": "Secondary", "value": "S1"}],
+		"auxiliaryFields": [{"key": "a", "label": "Auxiliary", "value": "A1"}],
+		"backFields": [{"key": "b", "label": "Terms", "value": "Back text"}]`
+	extra := map[string]string{
+		"boardingPass": `, "transitType": "PKTransitTypeAir"}`,
+		"eventTicket":  `, "additionalInfoFields": [{"key": "i", "label": "Parking", "value": "Lot B"}]}`,
+	}[style]
+	if extra == "" {
+		extra = "}"
+	}
+	data = strings.Replace(data, style+"Fields", fields+extra, 1)
+
+	p, err := passkit.DecodePass([]byte(data), passkit.DecodeStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestHTML(t *testing.T) {
+	for _, style := range []string{"boardingPass", "coupon", "eventTicket", "generic", "storeCard"} {
+		t.Run(style, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := New(testPass(t, style), nil).HTML(&buf); err != nil {
+				t.Fatal(err)
+			}
+			html := buf.String()
+
+			want := []string{
+				`class="pass front ` + style + `"`,
+				"<title>Preview</title>",
+				`<div class="logotext">Example Co</div>`,
+				`<div class="label">Header</div><div class="value">H1</div>`,
+				`<div class="label">From</div><div class="value">SFO</div>`,
+				`<div class="label">Secondary</div><div class="value">S1</div>`,
+				`<div class="label">Auxiliary</div><div class="value">A1</div>`,
+				`<div class="label">Terms</div><div class="value">Back text</div>`,
+				`<div class="barcode"><img src="data:image/png;base64,`,
+			}
+			switch style {
+			case "boardingPass":
+				want = append(want, `<div class="transit">✈</div>`)
+			case "eventTicket":
+				want = append(want, `<div class="label">Parking</div><div class="value">Lot B</div>`)
+			}
+
+			for _, s := range want {
+				if !strings.Contains(html, s) {
+					t.Errorf("HTML has no %s", s)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{field: `{"value": "Plain"}`, want: "Plain"},
+		{field: `{"value": 42}`, want: "42"},
+		{field: `{"value": 1.5}`, want: "1.5"},
+		{field: `{"value": 19.9, "currencyCode": "USD"}`, want: "USD 19.90"},
+		{field: `{"value": 0.25, "numberStyle": "PKNumberStylePercent"}`, want: "25%"},
+		{field: `{"value": 1200, "numberStyle": "PKNumberStyleScientific"}`, want: "1.2E+03"},
+		{field: `{"value": "2024-06-20T19:30:00-07:00"}`, want: "2024-06-20T19:30:00-07:00"},
+		{field: `{"value": "2024-06-20T19:30:00-07:00", "dateStyle": "PKDateStyleShort"}`, want: "6/20/24"},
+		{field: `{"value": "2024-06-20T19:30:00-07:00", "dateStyle": "PKDateStyleMedium", "timeStyle": "PKTimeStyleShort"}`, want: "Jun 20, 2024 at 7:30 PM"},
+		{field: `{"value": "2024-06-20T19:30:00-07:00", "dateStyle": "PKDateStyleFull"}`, want: "Thursday, June 20, 2024"},
+		{field: `{"value": "2024-06-20T19:30:00-07:00", "timeStyle": "PKTimeStyleMedium"}`, want: "7:30:00 PM"},
+	}
+
+	localize := func(s string) string { return s }
+	for _, tt := range tests {
+		var f passkit.PassFieldContent
+		if err := json.Unmarshal([]byte(tt.field), &f); err != nil {
+			t.Fatalf("%s: %v", tt.field, err)
+		}
+
+		if got := formatValue(f, localize); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestPNG(t *testing.T) {
+	p, err := passkit.DecodePass([]byte(`{
+		"formatVersion": 1,
+		"passTypeIdentifier": "pass.com.example",
+		"serialNumber": "1",
+		"teamIdentifier": "ABCDE12345",
+		"organizationName": "Example",
+		"description": "Empty coupon",
+		"coupon": {}
+	}`), passkit.DecodeStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pv := range []*Preview{New(p, nil), New(testPass(t, "eventTicket"), nil)} {
+		for _, side := range []Side{Front, Back} {
+			var buf bytes.Buffer
+			if err := pv.PNG(&buf, side); err != nil {
+				t.Fatalf("%s side %d: %v", pv.Pass.Description, side, err)
+			}
+
+			img, err := png.Decode(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := img.Bounds(); b.Dx() != imageWidth || b.Dy() == 0 {
+				t.Errorf("%s side %d: image is %v", pv.Pass.Description, side, b)
+			}
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "short", want: []string{"short"}},
+		{in: "one two three", want: []string{"one", "two", "three"}},
+		{in: "abcdefghijk", want: []string{"abcde", "fghij", "k"}},
+		{in: "ab cd\nef", want: []string{"ab cd", "ef"}},
+	}
+
+	for _, tt := range tests {
+		got := wrap(tt.in, 5)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrap(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}